@@ -0,0 +1,258 @@
+package check
+
+import (
+	"context"
+	"encoding"
+	"fmt"
+	"math"
+	"mime/multipart"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gowww/i18n"
+)
+
+// TimeLayouts are the layouts tried in order by Bind to parse a time.Time field, and by the date rules (like DateRange) without layouts.
+var TimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04", // HTML datetime-local input.
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+// Bind decodes form into the struct pointed by dst, using the same keys as FromStruct.
+//
+// Supported field types are strings, integers, floats, booleans (see strconv.ParseBool, plus "on", "off", "yes" and "no"), time.Time (see TimeLayouts), encoding.TextUnmarshaler implementations, *multipart.FileHeader, slices and pointers of these types, and nested structs.
+// Fields without value (or with an empty value) are left unchanged.
+//
+// A value that cannot be converted sets an error for its key: ErrNotInteger, ErrNotNumber, ErrMin or ErrMax for numbers, and ErrInvalid for other types.
+//
+// It panics if dst is not a pointer to a struct.
+// Result is guaranteed to be non-nil.
+func Bind(form *multipart.Form, dst interface{}) Errors {
+	rv := reflect.ValueOf(dst)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		panic(fmt.Sprintf("check: %T is not a pointer to a struct", dst))
+	}
+	errs := make(Errors)
+	if form == nil {
+		return errs
+	}
+	bindStruct(errs, form, "", rv.Elem())
+	return errs
+}
+
+// CheckBind works like CheckClean and, if there are no errors, binds the cleaned form to dst (see Bind).
+//
+// Result is guaranteed to be non-nil.
+func (c Checker) CheckBind(form *multipart.Form, dst interface{}, opts ...Option) Errors {
+	return c.Ordered().CheckBind(form, dst, opts...)
+}
+
+// CheckRequestBind works like CheckRequest and CheckBind.
+//
+// Result is guaranteed to be non-nil.
+func (c Checker) CheckRequestBind(r *http.Request, dst interface{}, opts ...Option) Errors {
+	return c.Ordered().CheckRequestBind(r, dst, opts...)
+}
+
+// CheckBind works like Checker.CheckBind.
+func (c OrderedChecker) CheckBind(form *multipart.Form, dst interface{}, opts ...Option) Errors {
+	form, errs, _ := c.check(context.Background(), form, opts)
+	if errs.NotEmpty() {
+		return errs
+	}
+	return Bind(form, dst)
+}
+
+// CheckRequestBind works like Checker.CheckRequestBind.
+func (c OrderedChecker) CheckRequestBind(r *http.Request, dst interface{}, opts ...Option) Errors {
+	form, errs, err := requestForm(r, c, opts)
+	if err != nil {
+		return requestErrors(err)
+	}
+	if errs != nil {
+		return errs
+	}
+	return c.CheckBind(form, dst, opts...)
+}
+
+func bindStruct(errs Errors, form *multipart.Form, prefix string, v reflect.Value) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		key, ok := structKey(f)
+		if !ok {
+			continue
+		}
+		fv := v.Field(i)
+		if !fv.CanSet() {
+			continue
+		}
+		ft := f.Type
+		for ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if ft != typeFileHeader.Elem() && structNested(ft) {
+			if f.Anonymous && f.Tag.Get("form") == "" && f.Tag.Get("json") == "" {
+				bindStruct(errs, form, prefix, allocate(fv))
+			} else if formHasPrefix(form, prefix+key+".") {
+				bindStruct(errs, form, prefix+key+".", allocate(fv))
+			}
+			continue
+		}
+		key = prefix + key
+		switch f.Type {
+		case typeFileHeader:
+			if files := form.File[key]; len(files) > 0 {
+				fv.Set(reflect.ValueOf(files[0]))
+			}
+			continue
+		case typeFileHeaders:
+			if files := form.File[key]; len(files) > 0 {
+				fv.Set(reflect.ValueOf(files))
+			}
+			continue
+		}
+		values := form.Value[key]
+		if len(values) == 0 {
+			continue
+		}
+		if f.Type.Kind() == reflect.Slice && f.Type.Elem().Kind() != reflect.Uint8 {
+			s := reflect.MakeSlice(f.Type, 0, len(values))
+			for _, value := range values {
+				if value == "" {
+					continue
+				}
+				ev := reflect.New(f.Type.Elem()).Elem()
+				if err := bindValue(allocate(ev), value); err != nil {
+					errs.Add(key, err)
+					continue
+				}
+				s = reflect.Append(s, ev)
+			}
+			fv.Set(s)
+			continue
+		}
+		if values[0] == "" {
+			continue
+		}
+		nv := reflect.New(f.Type).Elem()
+		if err := bindValue(allocate(nv), values[0]); err != nil {
+			errs.Add(key, err)
+			continue
+		}
+		fv.Set(nv)
+	}
+}
+
+// allocate allocates the nil pointers of v and returns the final value.
+func allocate(v reflect.Value) reflect.Value {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+// formHasPrefix tells if form has a value or a file with a key starting with prefix.
+func formHasPrefix(form *multipart.Form, prefix string) bool {
+	for _, k := range formAllKeys(form) {
+		if len(k) > len(prefix) && k[:len(prefix)] == prefix {
+			return true
+		}
+	}
+	return false
+}
+
+// bindValue sets v from string s.
+func bindValue(v reflect.Value, s string) *Error {
+	if v.Type() == typeTime {
+		for _, layout := range TimeLayouts {
+			if t, err := time.Parse(layout, s); err == nil {
+				v.Set(reflect.ValueOf(t))
+				return nil
+			}
+		}
+		return &Error{Error: ErrInvalid}
+	}
+	if v.CanAddr() {
+		if u, ok := v.Addr().Interface().(encoding.TextUnmarshaler); ok {
+			if err := u.UnmarshalText([]byte(s)); err != nil {
+				return &Error{Error: ErrInvalid}
+			}
+			return nil
+		}
+	}
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, ok := parseBool(s)
+		if !ok {
+			return &Error{Error: ErrInvalid}
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return intError(err, n)
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if len(s) > 0 && s[0] == '-' {
+			if _, err := strconv.ParseInt(s, 10, 64); err == nil {
+				return &Error{Error: ErrMin, Args: []interface{}{i18n.TransInt(0)}}
+			}
+		}
+		n, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			if ne, ok := err.(*strconv.NumError); ok && ne.Err == strconv.ErrRange {
+				return &Error{Error: ErrMax, Args: []interface{}{i18n.TransFloat64(n)}}
+			}
+			return &Error{Error: ErrNotInteger}
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil || math.IsInf(n, 0) || math.IsNaN(n) {
+			return &Error{Error: ErrNotNumber}
+		}
+		v.SetFloat(n)
+	case reflect.Slice: // []byte
+		v.SetBytes([]byte(s))
+	default:
+		return &Error{Error: ErrInvalid}
+	}
+	return nil
+}
+
+// parseBool parses s like strconv.ParseBool, also accepting "on", "off", "yes" and "no" (case-insensitive).
+// "on" is the value sent by an HTML checkbox without value attribute.
+func parseBool(s string) (b, ok bool) {
+	switch strings.ToLower(s) {
+	case "on", "yes":
+		return true, true
+	case "off", "no":
+		return false, true
+	}
+	b, err := strconv.ParseBool(s)
+	return b, err == nil
+}
+
+// intError returns the error for a failed integer parsing, where n is the limit for an out of range number.
+func intError(err error, n int64) *Error {
+	if ne, ok := err.(*strconv.NumError); ok && ne.Err == strconv.ErrRange {
+		if n < 0 {
+			return &Error{Error: ErrMin, Args: []interface{}{i18n.TransFloat64(n)}}
+		}
+		return &Error{Error: ErrMax, Args: []interface{}{i18n.TransFloat64(n)}}
+	}
+	return &Error{Error: ErrNotInteger}
+}
@@ -0,0 +1,115 @@
+package check
+
+import (
+	"mime/multipart"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type testBindStruct struct {
+	Name     string                  `form:"name"`
+	Age      int                     `form:"age"`
+	Small    int8                    `form:"small"`
+	Count    uint                    `form:"count"`
+	Price    *float64                `form:"price"`
+	Admin    bool                    `form:"admin"`
+	Birth    time.Time               `form:"birth"`
+	Tags     []string                `form:"tags"`
+	Scores   []int                   `form:"scores"`
+	Picture  *multipart.FileHeader   `form:"picture"`
+	Pictures []*multipart.FileHeader `form:"pictures"`
+	Address  struct {
+		City string `form:"city"`
+	} `form:"address"`
+	Untouched string `form:"untouched"`
+}
+
+func TestBind(t *testing.T) {
+	picture := &multipart.FileHeader{Filename: "picture.png"}
+	form := &multipart.Form{
+		Value: map[string][]string{
+			"name":         {"foo"},
+			"age":          {"42"},
+			"price":        {"12.50"},
+			"admin":        {"on"},
+			"birth":        {"2000-01-02"},
+			"tags":         {"a", "b"},
+			"scores":       {"1", "", "3"},
+			"address.city": {"Paris"},
+			"untouched":    {""},
+		},
+		File: map[string][]*multipart.FileHeader{
+			"picture":  {picture},
+			"pictures": {picture, picture},
+		},
+	}
+	v := testBindStruct{Untouched: "keep"}
+	if errs := Bind(form, &v); errs.NotEmpty() {
+		t.Fatalf("Bind: unexpected errors %v", errs)
+	}
+	price := 12.5
+	want := testBindStruct{
+		Name:      "foo",
+		Age:       42,
+		Price:     &price,
+		Admin:     true,
+		Birth:     time.Date(2000, 1, 2, 0, 0, 0, 0, time.UTC),
+		Tags:      []string{"a", "b"},
+		Scores:    []int{1, 3},
+		Picture:   picture,
+		Pictures:  []*multipart.FileHeader{picture, picture},
+		Untouched: "keep",
+	}
+	want.Address.City = "Paris"
+	if !reflect.DeepEqual(want, v) {
+		t.Errorf("Bind:\nwant %+v\ngot  %+v", want, v)
+	}
+}
+
+func TestBindBool(t *testing.T) {
+	for s, want := range map[string]bool{"on": true, "Yes": true, "true": true, "1": true, "off": false, "no": false, "false": false} {
+		v := testBindStruct{Admin: !want}
+		if errs := Bind(&multipart.Form{Value: map[string][]string{"admin": {s}}}, &v); errs.NotEmpty() || v.Admin != want {
+			t.Errorf("Bind(%q): want %v, got %v with errors %v", s, want, v.Admin, errs)
+		}
+	}
+}
+
+func TestBindErrors(t *testing.T) {
+	form := &multipart.Form{Value: map[string][]string{
+		"age":    {"a"},
+		"small":  {"300"},
+		"count":  {"-1"},
+		"price":  {"x"},
+		"admin":  {"maybe"},
+		"birth":  {"yesterday"},
+		"scores": {"1", "b"},
+	}}
+	want := map[string][]string{
+		"age":    {"notInteger"},
+		"small":  {"max:127"},
+		"count":  {"min:0"},
+		"price":  {"notNumber"},
+		"admin":  {"invalid"},
+		"birth":  {"invalid"},
+		"scores": {"notInteger"},
+	}
+	got := Bind(form, new(testBindStruct)).StringMap()
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("Bind:\nwant %v\ngot  %v", want, got)
+	}
+}
+
+func TestCheckerCheckBind(t *testing.T) {
+	c := Checker{"name": {Trim, Required}, "age": {Integer}}
+	var v testBindStruct
+	errs := c.CheckBind(&multipart.Form{Value: map[string][]string{"name": {" foo "}, "age": {"a"}}}, &v)
+	if !errs.Has("age") || v.Name != "" {
+		t.Errorf("Checker.CheckBind: want errors and no binding, got %v and %+v", errs, v)
+	}
+	errs = c.CheckBind(&multipart.Form{Value: map[string][]string{"name": {" foo "}, "age": {"2"}}}, &v)
+	if errs.NotEmpty() || v.Name != "foo" || v.Age != 2 {
+		t.Errorf("Checker.CheckBind: want cleaned values binding, got %v and %+v", errs, v)
+	}
+}
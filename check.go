@@ -2,66 +2,402 @@
 package check
 
 import (
+	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
+	"mime"
 	"mime/multipart"
 	"net/http"
+	"reflect"
+	"sort"
 	"strings"
+	"sync"
 )
 
-var errNoFileProvided = errors.New("check: no file provided")
+var (
+	errNoFileProvided = errors.New("check: no file provided")
+	errJSONTooLarge   = errors.New("check: JSON body too large")
+)
+
+// Request errors, returned (wrapped with their cause) by Checker.CheckRequestContext when the request form cannot be parsed.
+// Use errors.Is to test them.
+var (
+	ErrRequestTooLarge  = errors.New("check: request body too large")
+	ErrMalformedRequest = errors.New("check: malformed request")
+)
+
+// defaultMaxMemory is the maximum bytes of a multipart request body stored in memory, when the MaxMemory option is not used.
+const defaultMaxMemory = 32 << 20 // 32 MB
 
 // A Checker contains keys with their checking rules.
+// Keys are checked in alphabetical order (see OrderedChecker for a declared order).
+//
+// A key can be a path to a nested or repeated field, with segments separated by dots or enclosed in brackets.
+// A "*" segment matches any segment, so "items[*].quantity" matches "items[0].quantity", "items.1.quantity" and so on.
+// Rules are then applied once for each matching key and errors are set for this concrete key.
+//
+// When checking a request, a key can be qualified by the source of its values: "query:", "body:", "header:", "cookie:" or "path:" (for http.Request.PathValue, since Go 1.22).
+// So "query:page" only has the value from the URL query and "header:X-Request-ID" the values of this header.
+// Errors are set for the qualified key.
+// An unqualified key has the values of the URL query and the body.
 type Checker map[string][]Rule
 
 // Check makes the check for a multipart.Form (values and files) and returns errors.
 //
+// When a rule fails to make its check (like on a database outage), the key has an ErrInternal error.
+// Use CheckE to get these failures separately.
+//
 // Result is guaranteed to be non-nil.
-func (c Checker) Check(form *multipart.Form) Errors {
+func (c Checker) Check(form *multipart.Form, opts ...Option) Errors {
+	_, errs, _ := c.Ordered().check(context.Background(), form, opts)
+	return errs
+}
+
+// CheckE works like Check but the first failure of a rule is returned as a *RuleError, instead of being an ErrInternal error for the key.
+//
+// Errors result is guaranteed to be non-nil.
+func (c Checker) CheckE(form *multipart.Form, opts ...Option) (Errors, error) {
+	return c.CheckContext(context.Background(), form, opts...)
+}
+
+// CheckContext works like CheckE, with a context.
+// The context is given to the rules made with WithContext (like Unique).
+//
+// If ctx is done during the check, remaining rules are not applied and ctx.Err() is returned.
+//
+// Errors result is guaranteed to be non-nil.
+func (c Checker) CheckContext(ctx context.Context, form *multipart.Form, opts ...Option) (Errors, error) {
+	return c.Ordered().CheckContext(ctx, form, opts...)
+}
+
+// A checkJob contains the rules to apply for a single form key.
+type checkJob struct {
+	key   string
+	rules []Rule
+}
+
+// check makes the check for a copy of form, which is returned with the values changed by the sanitizers.
+func (c OrderedChecker) check(ctx context.Context, form *multipart.Form, opts []Option) (*multipart.Form, Errors, error) {
+	o := newOptions(opts)
+	if form == nil {
+		form = new(multipart.Form)
+	}
+	form = &multipart.Form{Value: cloneValues(form.Value), File: form.File} // New form for this check only, so sanitizers don't change the original values.
+	state := &checkState{ctx: ctx, form: form, scenarios: o.scenarios, strict: o.strict, allow: o.allow, partial: o.partial, only: o.only, clock: o.clock}
+	formStates.Store(form, state)
+	defer formStates.Delete(form)
+	var allJobs []checkJob
+	for _, kr := range c {
+		for _, k := range formKeys(form, kr.Key) {
+			allJobs = append(allJobs, checkJob{k, kr.Rules})
+		}
+	}
+	jobs := o.filterJobs(form, allJobs)
+	var errs Errors
+	var err error
+	if o.workers > 1 && len(jobs) > 1 {
+		errs, err = checkParallel(ctx, form, jobs, o.workers)
+	} else {
+		errs = make(Errors)
+		for _, job := range jobs {
+			if err = job.run(ctx, errs, form); err != nil {
+				break
+			}
+		}
+	}
+	if o.strict {
+		// Keys nested in a key left out by the Partial or Only options are not checked, so they are known.
+		parents := state.nested
+		for _, job := range skippedJobs(allJobs, jobs) {
+			parents = append(parents, job.key)
+		}
+		for _, k := range unknownKeys(form, allJobs, parents, o.allow) {
+			errs.Add(k, &Error{Error: ErrUnknownField})
+		}
+	}
+	return form, errs, err
+}
+
+// skippedJobs returns the jobs of all that are not in jobs, which is a subsequence of all.
+func skippedJobs(all, jobs []checkJob) []checkJob {
+	var skipped []checkJob
+	for _, job := range all {
+		if len(jobs) > 0 && jobs[0].key == job.key {
+			jobs = jobs[1:]
+			continue
+		}
+		skipped = append(skipped, job)
+	}
+	return skipped
+}
+
+// unknownKeys returns the form keys that are not checked by jobs, not nested in parents and not allowed by allow patterns, in alphabetical order.
+// A parent key of a checked key without values and files (like "items" for "items[*].sku", set empty by a JSON array) is known.
+// So is the unqualified key of a checked query or body key (like "page" for "query:page"), when all its values come from this source.
+func unknownKeys(form *multipart.Form, jobs []checkJob, parents, allow []string) []string {
+	known := make(map[string]bool, len(jobs))
+	jobKeys := make([]string, 0, len(jobs))
+	for _, job := range jobs {
+		known[job.key] = true
+		jobKeys = append(jobKeys, job.key)
+		if source, name := sourceKey(job.key); (source == sourceQuery || source == sourceBody) && sameSourceValues(form, name, job.key) {
+			known[name] = true
+			jobKeys = append(jobKeys, name)
+		}
+	}
+	var keys []string
+	for _, k := range formAllKeys(form) {
+		if known[k] || nestedInAny(parents, k) || matchAnyKey(allow, k) {
+			continue
+		}
+		if len(form.Value[k]) == 0 && len(form.File[k]) == 0 && matchAnyParentKey(jobKeys, k) {
+			continue
+		}
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// sameSourceValues tells if key has the same values and files as its source qualified key.
+func sameSourceValues(form *multipart.Form, key, qualified string) bool {
+	return reflect.DeepEqual(form.Value[key], form.Value[qualified]) && reflect.DeepEqual(form.File[key], form.File[qualified])
+}
+
+// checkParallel runs jobs with n goroutines at most.
+// Each job has its own errors map, merged in the jobs order at the end.
+//
+// A job with sanitizers (see sanitizes) changes the values of its key, so it runs alone, after the previous jobs and before the next ones.
+// Jobs then see the same values as in a sequential check.
+func checkParallel(ctx context.Context, form *multipart.Form, jobs []checkJob, n int) (Errors, error) {
+	jobsErrs := make([]Errors, len(jobs))
+	sem := make(chan struct{}, n)
+	var wg sync.WaitGroup
+	for i := range jobs {
+		jobsErrs[i] = make(Errors)
+		if sanitizes(jobs[i].rules) {
+			wg.Wait()
+			jobs[i].run(ctx, jobsErrs[i], form)
+			continue
+		}
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(i int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			jobs[i].run(ctx, jobsErrs[i], form)
+		}(i)
+	}
+	wg.Wait()
 	errs := make(Errors)
-	for key, rules := range c {
-		for _, rule := range rules {
-			rule(errs, form, key)
+	for _, jobErrs := range jobsErrs {
+		errs.Merge(jobErrs)
+	}
+	return errs, ctx.Err()
+}
+
+// run applies the job rules in order, until ctx is done.
+func (job checkJob) run(ctx context.Context, errs Errors, form *multipart.Form) error {
+	return applyRules(ctx, errs, form, job.key, job.rules)
+}
+
+// applyRules applies rules in order for key, until ctx is done or until an Optional or Nullable marker skips the remaining rules.
+func applyRules(ctx context.Context, errs Errors, form *multipart.Form, key string, rules []Rule) error {
+	for _, rule := range rules {
+		if err := ctx.Err(); err != nil {
+			return err
 		}
+		if skip, ok := marker(rule, form, key); ok {
+			if skip {
+				return nil
+			}
+			continue
+		}
+		rule(errs, form, key)
 	}
-	return errs
+	return nil
+}
+
+// CheckPartial works like Check but only the keys present in form are checked (see Partial).
+//
+// Result is guaranteed to be non-nil.
+func (c Checker) CheckPartial(form *multipart.Form, opts ...Option) Errors {
+	return c.Check(form, append([]Option{Partial()}, opts...)...)
+}
+
+// CheckOnly works like Check but only keys are checked (see Only).
+//
+// Result is guaranteed to be non-nil.
+func (c Checker) CheckOnly(form *multipart.Form, keys ...string) Errors {
+	return c.Check(form, Only(keys...))
+}
+
+// CheckClean works like Check but also returns the checked form, with the values changed by the sanitizers (like Trim).
+// The original form is never changed.
+//
+// Results are guaranteed to be non-nil.
+func (c Checker) CheckClean(form *multipart.Form, opts ...Option) (*multipart.Form, Errors) {
+	return c.Ordered().CheckClean(form, opts...)
 }
 
 // CheckValues makes the check for a values map (key to multiple values) and returns errors.
 //
 // Result is guaranteed to be non-nil.
-func (c Checker) CheckValues(values map[string][]string) Errors {
-	return c.Check(&multipart.Form{Value: values})
+func (c Checker) CheckValues(values map[string][]string, opts ...Option) Errors {
+	return c.Check(&multipart.Form{Value: values}, opts...)
 }
 
 // CheckFiles makes the check for a files map (key to multiple files) and returns errors.
 //
 // Result is guaranteed to be non-nil.
-func (c Checker) CheckFiles(files map[string][]*multipart.FileHeader) Errors {
-	return c.Check(&multipart.Form{File: files})
+func (c Checker) CheckFiles(files map[string][]*multipart.FileHeader, opts ...Option) Errors {
+	return c.Check(&multipart.Form{File: files}, opts...)
 }
 
 // CheckRequest makes the check for an HTTP request and returns errors.
 //
 // Request data can have multiple values with the same key (or field).
-// In this case, all values are checked and if one fails, the error is set for the whole key.
+// In this case, all values are checked and if one fails, the error is set for the whole key, with the positions of the failing values in Error.Indexes (see Errors.Indexed).
+//
+// When the request has an "application/json" content type, its body is read as in CheckJSON (and merged with the query values).
+// The body is then reset so it can be read again by the handler.
+//
+// The request body is parsed with the limits of the MaxMemory and MaxBodySize options, unless r.MultipartForm is already set.
+// If it cannot be parsed, no check is made and the result only has an error for the empty key "": ErrMaxBodySize when the body is too large, ErrBadRequest otherwise.
+// Its Cause is the parsing error, wrapping ErrRequestTooLarge or ErrMalformedRequest.
+// Use CheckRequestContext to get the parsing error instead.
 //
 // Result is guaranteed to be non-nil.
-func (c Checker) CheckRequest(r *http.Request) Errors {
-	if r.Form == nil {
-		r.ParseMultipartForm(32 << 20) // 32 MB
-	}
-	form := &multipart.Form{Value: r.Form}
-	if r.MultipartForm != nil {
-		if r.MultipartForm.Value != nil {
-			for k, v := range r.MultipartForm.Value {
-				form.Value[k] = append(form.Value[k], v...)
-			}
+func (c Checker) CheckRequest(r *http.Request, opts ...Option) Errors {
+	return c.Ordered().CheckRequest(r, opts...)
+}
+
+// CheckRequestContext works like CheckRequest and CheckContext, with the request context.
+// If the request body cannot be parsed, no check is made and the error wraps ErrRequestTooLarge or ErrMalformedRequest.
+//
+// Errors result is guaranteed to be non-nil.
+func (c Checker) CheckRequestContext(r *http.Request, opts ...Option) (Errors, error) {
+	return c.Ordered().CheckRequestContext(r, opts...)
+}
+
+// CheckRequestClean works like CheckRequest and CheckClean.
+//
+// Results are guaranteed to be non-nil.
+func (c Checker) CheckRequestClean(r *http.Request, opts ...Option) (*multipart.Form, Errors) {
+	return c.Ordered().CheckRequestClean(r, opts...)
+}
+
+// requestForm returns a form made from the values and files of r, with the values of the source qualified keys of c (see Checker).
+// The form is never nil, even with an error.
+//
+// With the Stream option, errs is not nil when the upload has been aborted because of the file rules of c.
+func requestForm(r *http.Request, c OrderedChecker, opts []Option) (form *multipart.Form, errs Errors, err error) {
+	o := newOptions(opts)
+	if o.maxBodySize > 0 && r.Body != nil {
+		r.Body = http.MaxBytesReader(nil, r.Body, o.maxBodySize)
+	}
+	maxMemory := o.maxMemory
+	if maxMemory <= 0 {
+		maxMemory = defaultMaxMemory
+	}
+	form = &multipart.Form{Value: make(map[string][]string)}
+	var body map[string][]string
+	if isJSONRequest(r) {
+		if r.URL != nil {
+			form.Value = r.URL.Query()
+		}
+		body, err = requestJSONBody(r, maxMemory)
+		for k, v := range body {
+			form.Value[k] = append(form.Value[k], v...)
+		}
+	} else if r.MultipartForm != nil && r.PostForm == nil {
+		// A multipart form set without parsing the request is used as the parsed body, but its values are not in r.Form yet.
+		if r.Form != nil {
+			form.Value = cloneValues(r.Form)
+		} else if r.URL != nil {
+			form.Value = r.URL.Query()
+		}
+		body = r.MultipartForm.Value
+		for k, v := range body {
+			form.Value[k] = append(form.Value[k], v...)
 		}
 		form.File = r.MultipartForm.File
+	} else {
+		if o.stream && r.MultipartForm == nil {
+			if errs, err = streamRequest(r, c, maxMemory); errs != nil || err != nil && err != http.ErrNotMultipart {
+				return form, errs, requestError(err)
+			}
+		}
+		err = r.ParseForm() // Called first as ParseMultipartForm drops its error for a body that is not multipart.
+		if err == nil {
+			err = r.ParseMultipartForm(maxMemory) // Values of a multipart body are also set in r.Form and r.PostForm.
+			if err == http.ErrNotMultipart {
+				err = nil
+			}
+		}
+		if r.Form != nil {
+			form.Value = r.Form
+		}
+		body = r.PostForm
+		if r.MultipartForm != nil {
+			form.File = r.MultipartForm.File
+		}
+	}
+	addSourceValues(form, r, body, c.Keys())
+	return form, nil, requestError(err)
+}
+
+// requestErrors returns the result of a check for a request body that cannot be parsed because of err (from requestForm).
+// There is an ErrMaxBodySize or ErrBadRequest error for the empty key, as the request fails as a whole.
+func requestErrors(err error) Errors {
+	id := ErrBadRequest
+	if errors.Is(err, ErrRequestTooLarge) {
+		id = ErrMaxBodySize
+	}
+	return Errors{"": {{Error: id, Cause: err}}}
+}
+
+// requestError returns err wrapped with ErrRequestTooLarge or ErrMalformedRequest.
+func requestError(err error) error {
+	if err == nil {
+		return nil
 	}
-	return c.Check(form)
+	// http.MaxBytesReader has no error value to compare with before Go 1.19 (and http.MaxBytesError), so its message is used.
+	if errors.Is(err, multipart.ErrMessageTooLarge) || err == errJSONTooLarge || strings.Contains(err.Error(), "http: request body too large") {
+		return fmt.Errorf("%w: %v", ErrRequestTooLarge, err)
+	}
+	return fmt.Errorf("%w: %v", ErrMalformedRequest, err)
+}
+
+func isJSONRequest(r *http.Request) bool {
+	mt, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	return mt == "application/json"
+}
+
+// requestJSONBody returns the values of the JSON body of r, which cannot be larger than max bytes as it's read in memory.
+// The body is reset so it can be read again.
+func requestJSONBody(r *http.Request, max int64) (map[string][]string, error) {
+	if r.Body == nil {
+		return nil, nil
+	}
+	b, err := ioutil.ReadAll(io.LimitReader(r.Body, max+1))
+	r.Body.Close()
+	r.Body = ioutil.NopCloser(bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(b)) > max {
+		return nil, errJSONTooLarge
+	}
+	if len(bytes.TrimSpace(b)) == 0 {
+		return nil, nil
+	}
+	return jsonValues(bytes.NewReader(b))
 }
 
 func fileType(file *multipart.FileHeader) (string, error) {
@@ -72,18 +408,28 @@ func fileType(file *multipart.FileHeader) (string, error) {
 	if err != nil {
 		return "", err
 	}
-	defer f.Seek(0, io.SeekStart) // Reset reader.
+	defer f.Close()
 	fh := make([]byte, 512)
-	if _, err = f.Read(fh); err != nil {
+	n, err := io.ReadFull(f, fh)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
 		return "", err
 	}
-	ct := http.DetectContentType(fh)
+	ct := http.DetectContentType(fh[:n])
 	if i := strings.IndexByte(ct, ';'); i != -1 {
 		ct = ct[:i]
 	}
 	return ct, nil
 }
 
+// cloneValues returns a deep copy of values.
+func cloneValues(values map[string][]string) map[string][]string {
+	m := make(map[string][]string, len(values))
+	for k, v := range values {
+		m[k] = append([]string(nil), v...)
+	}
+	return m
+}
+
 func sliceContainsString(ss []string, s string) bool {
 	for _, e := range ss {
 		if s == e {
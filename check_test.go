@@ -1,8 +1,17 @@
 package check
 
 import (
+	"bytes"
+	"errors"
 	"mime/multipart"
+	"net/http"
 	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gowww/i18n"
 )
 
 var (
@@ -47,3 +56,87 @@ func TestCheckerCheckRequest(t *testing.T) {
 	}
 }
 */
+
+func testMultipartRequest(t *testing.T, values map[string]string) *http.Request {
+	body := new(bytes.Buffer)
+	w := multipart.NewWriter(body)
+	for k, v := range values {
+		if err := w.WriteField(k, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	w.Close()
+	r, _ := http.NewRequest("POST", "/?page=1", body)
+	r.Header.Set("Content-Type", w.FormDataContentType())
+	return r
+}
+
+func TestCheckerCheckRequestParsing(t *testing.T) {
+	c := Checker{"email": {Required, Email}, "page": {MaxCount(2)}} // Query and body values.
+
+	r := testMultipartRequest(t, map[string]string{"email": "foo@example.com", "page": "2"})
+	errs, err := c.CheckRequestContext(r)
+	if err != nil || errs.NotEmpty() {
+		t.Errorf("Checker.CheckRequestContext: want no error, got %v and %v", err, errs)
+	}
+	if want := []string{"foo@example.com"}; !reflect.DeepEqual(want, r.Form["email"]) {
+		t.Errorf("Checker.CheckRequestContext: request form changed: want %v, got %v", want, r.Form["email"])
+	}
+
+	r, _ = http.NewRequest("POST", "/?page=1", nil)
+	r.MultipartForm = &multipart.Form{Value: map[string][]string{"email": {"foo"}, "page": {"2", "3"}}}
+	errs, err = c.CheckRequestContext(r)
+	want := Errors{"email": {{Error: ErrNotEmail, Indexes: []int{0}}}, "page": {{Error: ErrMaxCount, Args: []interface{}{i18n.TransInt(2)}}}}
+	if err != nil || !reflect.DeepEqual(want, errs) {
+		t.Errorf("Checker.CheckRequestContext with a set MultipartForm:\nwant %v and no error\ngot  %v and %v", want, errs, err)
+	}
+
+	r = testMultipartRequest(t, map[string]string{"email": strings.Repeat("a", 1000)})
+	if _, err = c.CheckRequestContext(r, MaxBodySize(100)); !errors.Is(err, ErrRequestTooLarge) {
+		t.Errorf("Checker.CheckRequestContext: want %v, got %v", ErrRequestTooLarge, err)
+	}
+
+	fields := make(map[string]string)
+	for i := 0; i < 2000; i++ { // More parts than accepted by multipart.Reader.ReadForm.
+		fields["field"+strconv.Itoa(i)] = "a"
+	}
+	r = testMultipartRequest(t, fields)
+	if _, err = c.CheckRequestContext(r); !errors.Is(err, ErrRequestTooLarge) {
+		t.Errorf("Checker.CheckRequestContext: want %v for too many parts, got %v", ErrRequestTooLarge, err)
+	}
+
+	r, _ = http.NewRequest("POST", "/", strings.NewReader("--foo\r\nbroken"))
+	r.Header.Set("Content-Type", "multipart/form-data; boundary=foo")
+	if _, err = c.CheckRequestContext(r); !errors.Is(err, ErrMalformedRequest) {
+		t.Errorf("Checker.CheckRequestContext: want %v, got %v", ErrMalformedRequest, err)
+	}
+
+	r, _ = http.NewRequest("POST", "/", strings.NewReader("--foo\r\nbroken"))
+	r.Header.Set("Content-Type", "multipart/form-data; boundary=foo")
+	errs = c.CheckRequest(r)
+	if e := errs.First(""); len(errs) != 1 || e == nil || e.Error != ErrBadRequest || !errors.Is(e.Cause, ErrMalformedRequest) {
+		t.Errorf("Checker.CheckRequest: want only %s error with cause for the empty key, got %v", ErrBadRequest.ID, errs)
+	}
+
+	r = testMultipartRequest(t, map[string]string{"email": strings.Repeat("a", 1000)})
+	if _, errs = c.CheckRequestClean(r, MaxBodySize(100)); errs.First("") == nil || errs.First("").Error != ErrMaxBodySize {
+		t.Errorf("Checker.CheckRequestClean: want %s error for the empty key, got %v", ErrMaxBodySize.ID, errs)
+	}
+
+	r, _ = http.NewRequest("POST", "/", strings.NewReader(`{"email":`))
+	r.Header.Set("Content-Type", "application/json")
+	if _, err = c.CheckRequestContext(r); !errors.Is(err, ErrMalformedRequest) {
+		t.Errorf("Checker.CheckRequestContext: want %v for JSON, got %v", ErrMalformedRequest, err)
+	}
+
+	r, _ = http.NewRequest("POST", "/", strings.NewReader(`{"email":"`+strings.Repeat("a", 1000)+`"}`))
+	r.Header.Set("Content-Type", "application/json")
+	if _, err = c.CheckRequestContext(r, MaxMemory(100)); !errors.Is(err, ErrRequestTooLarge) {
+		t.Errorf("Checker.CheckRequestContext: want %v for JSON larger than MaxMemory, got %v", ErrRequestTooLarge, err)
+	}
+	r, _ = http.NewRequest("POST", "/", strings.NewReader(`{"email":"`+strings.Repeat("a", defaultMaxMemory)+`"}`))
+	r.Header.Set("Content-Type", "application/json")
+	if errs = c.CheckRequest(r); errs.First("") == nil || errs.First("").Error != ErrMaxBodySize {
+		t.Errorf("Checker.CheckRequest: want %s error for JSON larger than the default memory limit, got %v", ErrMaxBodySize.ID, errs)
+	}
+}
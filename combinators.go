@@ -0,0 +1,171 @@
+package check
+
+import "mime/multipart"
+
+// All rule checks that value passes all the rules.
+// Rules are applied in order (Optional and Nullable markers included) and all their errors are set for the key.
+func All(rules ...Rule) Rule {
+	return sanitizerOf(func(errs Errors, form *multipart.Form, key string) {
+		scratch := make(Errors)
+		applyRules(formContext(form), scratch, form, key, rules)
+		errs.Merge(scratch)
+	}, rules)
+}
+
+// Any rule checks that value passes at least one of the rules.
+// If all rules fail, all their errors are set for the key.
+// When the key has multiple values (or files), each one must pass one of the rules (like with Each), and errors have the positions of the failing ones in Error.Indexes.
+//
+// For example, to accept an email or a phone number:
+//
+//	check.Any(check.Email, check.Phone)
+func Any(rules ...Rule) Rule {
+	one := func(errs Errors, form *multipart.Form, key string) {
+		scratches := make([]Errors, len(rules))
+		for i, rule := range rules {
+			scratches[i] = make(Errors)
+			rule(scratches[i], form, key)
+			if !scratches[i].Has(key) {
+				return
+			}
+		}
+		for _, scratch := range scratches {
+			errs.Merge(scratch)
+		}
+	}
+	each := Each(one)
+	return sanitizerOf(func(errs Errors, form *multipart.Form, key string) {
+		if form != nil && (len(form.Value[key]) > 1 || len(form.File[key]) > 1) {
+			each(errs, form, key)
+			return
+		}
+		one(errs, form, key)
+	}, rules)
+}
+
+// Not rule checks that value fails the rule.
+// If the rule passes, the errID error is set for the key.
+//
+// A rule failure (see Errors.Fail) is kept as is.
+func Not(rule Rule, errID *ErrorID) Rule {
+	return sanitizerOf(func(errs Errors, form *multipart.Form, key string) {
+		scratch := make(Errors)
+		rule(scratch, form, key)
+		if !scratch.Has(key) {
+			errs.Add(key, &Error{Error: errID})
+			return
+		}
+		for _, err := range scratch[key] {
+			if err.Error == ErrInternal {
+				errs.Add(key, err)
+			}
+		}
+	}, []Rule{rule})
+}
+
+// A Predicate tells if a condition is met for a key in form.
+type Predicate func(form *multipart.Form, key string) bool
+
+// When rule applies the rules (like All) only if predicate is true.
+//
+// For example, to check a VAT number only for a company:
+//
+//	check.When(func(form *multipart.Form, key string) bool {
+//		return len(form.Value["type"]) > 0 && form.Value["type"][0] == "company"
+//	}, check.Required, check.Alphanumeric)
+func When(predicate Predicate, rules ...Rule) Rule {
+	all := All(rules...)
+	return sanitizerOf(func(errs Errors, form *multipart.Form, key string) {
+		if predicate(form, key) {
+			all(errs, form, key)
+		}
+	}, []Rule{all})
+}
+
+// On rule applies the rules (like All) only if the check has the scenario (see Scenario).
+//
+// For example, to require a password on creation only:
+//
+//	"password": {check.On("create", check.Required), check.Optional, check.MinLen(8)},
+func On(scenario string, rules ...Rule) Rule {
+	all := All(rules...)
+	return sanitizerOf(func(errs Errors, form *multipart.Form, key string) {
+		if sliceContainsString(formState(form).scenarios, scenario) {
+			all(errs, form, key)
+		}
+	}, []Rule{all})
+}
+
+// Except rule applies the rules (like All) only if the check doesn't have the scenario (see Scenario).
+func Except(scenario string, rules ...Rule) Rule {
+	all := All(rules...)
+	return sanitizerOf(func(errs Errors, form *multipart.Form, key string) {
+		if !sliceContainsString(formState(form).scenarios, scenario) {
+			all(errs, form, key)
+		}
+	}, []Rule{all})
+}
+
+// Each rule applies the rules (like All) to each value and each file of the key separately, as if it was the only one.
+// Errors of all values and files are set for the key, with the positions of the failing ones in Error.Indexes.
+// A key without values and files is not checked.
+// A sanitizer (like Trim) changes each value of the key in the checked form, as when used without Each.
+func Each(rules ...Rule) Rule {
+	all := All(rules...)
+	return sanitizerOf(func(errs Errors, form *multipart.Form, key string) {
+		if form == nil {
+			return
+		}
+		for i, v := range form.Value[key] {
+			sub := &multipart.Form{Value: replaceValues(form.Value, key, []string{v}), File: form.File}
+			release := deriveForm(form, sub)
+			eachAll(all, errs, sub, key, i)
+			release()
+			if vv := sub.Value[key]; len(vv) == 1 && vv[0] != v { // Changed by a sanitizer.
+				form.Value[key][i] = vv[0]
+			}
+		}
+		for i, f := range form.File[key] {
+			sub := &multipart.Form{Value: form.Value, File: replaceFiles(form.File, key, []*multipart.FileHeader{f})}
+			release := deriveForm(form, sub)
+			eachAll(all, errs, sub, key, i)
+			release()
+		}
+	}, []Rule{all})
+}
+
+// eachAll applies all to the single value (or file) sub form and sets the errors of key with index i.
+func eachAll(all Rule, errs Errors, sub *multipart.Form, key string, i int) {
+	scratch := make(Errors)
+	all(scratch, sub, key)
+	for k, kErrs := range scratch {
+		for _, err := range kErrs {
+			if k == key && err.Error != ErrRequired && err.Error != ErrInternal {
+				ierr := *err
+				ierr.Indexes = []int{i}
+				err = &ierr
+			}
+			errs.Add(k, err)
+		}
+	}
+}
+
+// replaceValues returns a copy of values where key has vv.
+func replaceValues(values map[string][]string, key string, vv []string) map[string][]string {
+	m := make(map[string][]string, len(values))
+	for k, v := range values {
+		m[k] = v
+	}
+	m[key] = vv
+	return m
+}
+
+// replaceFiles returns a copy of files where key has ff.
+func replaceFiles(files map[string][]*multipart.FileHeader, key string, ff []*multipart.FileHeader) map[string][]*multipart.FileHeader {
+	m := make(map[string][]*multipart.FileHeader, len(files))
+	for k, f := range files {
+		m[k] = f
+	}
+	m[key] = ff
+	return m
+}
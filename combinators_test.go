@@ -0,0 +1,55 @@
+package check
+
+import (
+	"errors"
+	"mime/multipart"
+	"reflect"
+	"testing"
+)
+
+func TestCombinators(t *testing.T) {
+	fail := func(errs Errors, form *multipart.Form, key string) {
+		errs.Fail(key, errors.New("failure"))
+	}
+	isFoo := func(form *multipart.Form, key string) bool {
+		return len(form.Value["type"]) > 0 && form.Value["type"][0] == "foo"
+	}
+	cases := []struct {
+		values []string
+		rule   Rule
+		want   []*Error
+	}{
+		{[]string{"foo@example.com"}, Any(Email, Phone), nil},
+		{[]string{"0012345678901"}, Any(Email, Phone), nil},
+		{[]string{"foo"}, Any(Email, Phone), []*Error{{Error: ErrNotEmail}, {Error: ErrNotPhone}}},
+		{[]string{"a@b.cc", "0123456789"}, Any(Email, Phone), nil},
+		{[]string{"a@b.cc", "foo"}, Any(Email, Phone), []*Error{{Error: ErrNotEmail}, {Error: ErrNotPhone}}},
+		{[]string{"foo"}, All(Email, Phone), []*Error{{Error: ErrNotEmail}, {Error: ErrNotPhone}}},
+		{[]string{"foo@example.com"}, All(Email, Phone), []*Error{{Error: ErrNotPhone}}},
+		{[]string{"foo"}, Not(Email, ErrInvalid), nil},
+		{[]string{"foo@example.com"}, Not(Email, ErrInvalid), []*Error{{Error: ErrInvalid}}},
+		{[]string{"foo"}, When(isFoo, Email), []*Error{{Error: ErrNotEmail}}},
+		{[]string{"foo"}, When(func(*multipart.Form, string) bool { return false }, Email), nil},
+		{[]string{"1", "a", "200"}, Each(Integer, Max(100)), []*Error{{Error: ErrNotInteger}, {Error: ErrNotNumber}, {Error: ErrMax, Args: []interface{}{"100"}}}},
+	}
+	for i, c := range cases {
+		errs := make(Errors)
+		c.rule(errs, &multipart.Form{Value: map[string][]string{"key": c.values, "type": {"foo"}}}, "key")
+		got := errs["key"]
+		if !reflect.DeepEqual((Errors{"key": c.want}).StringMap()["key"], (Errors{"key": got}).StringMap()["key"]) {
+			t.Errorf("case %d: want %v, got %v", i, c.want, got)
+		}
+	}
+
+	errs := make(Errors)
+	Any(Email, Phone)(errs, &multipart.Form{Value: map[string][]string{"key": {"a@b.cc", "foo", "0123456789"}}}, "key")
+	if want := (Errors{"key": {{Error: ErrNotEmail, Indexes: []int{1}}, {Error: ErrNotPhone, Indexes: []int{1}}}}); !reflect.DeepEqual(want, errs) {
+		t.Errorf("Any with multiple values:\nwant %v\ngot  %v", want, errs)
+	}
+
+	errs = make(Errors)
+	Not(fail, ErrInvalid)(errs, &multipart.Form{}, "key")
+	if e := errs.First("key"); e == nil || e.Error != ErrInternal {
+		t.Errorf("Not: want failure to be kept, got %v", errs)
+	}
+}
@@ -0,0 +1,95 @@
+package check
+
+import (
+	"context"
+	"mime/multipart"
+	"sync"
+	"time"
+)
+
+// formStates maps the forms being checked to the state of their check.
+var formStates sync.Map
+
+// A checkState contains the check settings that rules can use.
+type checkState struct {
+	ctx       context.Context
+	form      *multipart.Form // Root form of the check.
+	scenarios []string
+	strict    bool
+	allow     []string
+	partial   bool
+	only      []string
+	clock     func() time.Time
+
+	mu     sync.Mutex
+	nested []string // Root form keys checked by Nested or Dive.
+}
+
+// now returns the current time of the check (see Clock).
+func (s *checkState) now() time.Time {
+	if s.clock == nil {
+		return time.Now()
+	}
+	return s.clock()
+}
+
+// derive returns the state of the check for a sub-form of a Nested rule, where only are the patterns of the Only option for the sub-form keys.
+func (s *checkState) derive(only []string) *checkState {
+	return &checkState{
+		ctx:       s.ctx,
+		form:      s.form,
+		scenarios: s.scenarios,
+		strict:    s.strict,
+		allow:     s.allow,
+		partial:   s.partial,
+		only:      only,
+		clock:     s.clock,
+	}
+}
+
+// addNested records that the keys nested in key of form are checked by a sub-checker, if form is the root form of the check.
+func (s *checkState) addNested(form *multipart.Form, key string) {
+	if s.form != form {
+		return
+	}
+	s.mu.Lock()
+	s.nested = append(s.nested, key)
+	s.mu.Unlock()
+}
+
+// A ContextRule is a Rule that also receives the context of the check.
+type ContextRule func(ctx context.Context, errs Errors, form *multipart.Form, key string)
+
+// WithContext returns a Rule calling r with the context of the check.
+// This context is the one given to Checker.CheckContext, the request context for Checker.CheckRequestContext or context.Background otherwise.
+func WithContext(r ContextRule) Rule {
+	return func(errs Errors, form *multipart.Form, key string) {
+		r(formContext(form), errs, form, key)
+	}
+}
+
+// formState returns the state of the check in progress for form.
+func formState(form *multipart.Form) *checkState {
+	if form != nil {
+		if state, ok := formStates.Load(form); ok {
+			return state.(*checkState)
+		}
+	}
+	return &checkState{ctx: context.Background()}
+}
+
+// formContext returns the context of the check in progress for form.
+func formContext(form *multipart.Form) context.Context {
+	return formState(form).ctx
+}
+
+// deriveForm makes sub a part of the check in progress for form, until release is called.
+// It's used by the combinators applying rules to a new form (like Each with each value), so these rules get the context, scenarios and clock of the check.
+func deriveForm(form, sub *multipart.Form) (release func()) {
+	if state, ok := formStates.Load(form); ok {
+		formStates.Store(sub, state)
+	}
+	return func() {
+		formStates.Delete(sub)
+	}
+}
@@ -0,0 +1,75 @@
+package check
+
+import (
+	"context"
+	"mime/multipart"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gowww/i18n"
+)
+
+type testContextKey struct{}
+
+func TestCheckerCheckContext(t *testing.T) {
+	ctx := context.WithValue(context.Background(), testContextKey{}, "foo")
+	var got interface{}
+	c := Checker{"key": {WithContext(func(ctx context.Context, errs Errors, form *multipart.Form, key string) {
+		got = ctx.Value(testContextKey{})
+	})}}
+	if _, err := c.CheckContext(ctx, &multipart.Form{}); err != nil {
+		t.Fatal(err)
+	}
+	if got != "foo" {
+		t.Errorf("WithContext: want context value %q, got %v", "foo", got)
+	}
+}
+
+func TestCheckerCheckContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var calls int
+	c := Checker{"key": {
+		func(errs Errors, form *multipart.Form, key string) {
+			calls++
+			cancel()
+		},
+		Required,
+	}}
+	errs, err := c.CheckContext(ctx, &multipart.Form{})
+	if err != context.Canceled {
+		t.Errorf("Checker.CheckContext: want error %v, got %v", context.Canceled, err)
+	}
+	if calls != 1 || !reflect.DeepEqual(Errors{}, errs) {
+		t.Errorf("Checker.CheckContext: want no rule applied after cancellation, got %d calls and errors %v", calls, errs)
+	}
+}
+
+func TestEachCheckState(t *testing.T) {
+	ctx := context.WithValue(context.Background(), testContextKey{}, "foo")
+	var got interface{}
+	var sub *multipart.Form
+	rule := WithContext(func(ctx context.Context, errs Errors, form *multipart.Form, key string) {
+		got = ctx.Value(testContextKey{})
+		sub = form
+	})
+	c := Checker{"key": {Each(rule, On("create", MinLen(20)), MinAge(18))}}
+	now := time.Date(2020, 6, 15, 0, 0, 0, 0, time.UTC)
+	errs, err := c.CheckContext(ctx, &multipart.Form{Value: map[string][]string{"key": {"2010-01-01"}}}, Scenario("create"), Clock(func() time.Time { return now }))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "foo" {
+		t.Errorf("Each: want context value %q, got %v", "foo", got)
+	}
+	want := Errors{"key": {
+		{Error: ErrMinLen, Args: []interface{}{i18n.TransInt(20)}, Indexes: []int{0}},
+		{Error: ErrMinAge, Args: []interface{}{i18n.TransInt(18)}, Indexes: []int{0}},
+	}}
+	if !reflect.DeepEqual(want, errs) {
+		t.Errorf("Each:\nwant %v\ngot  %v", want, errs)
+	}
+	if _, ok := formStates.Load(sub); ok {
+		t.Error("Each: want sub form released")
+	}
+}
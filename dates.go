@@ -0,0 +1,247 @@
+package check
+
+import (
+	"mime/multipart"
+	"strconv"
+	"time"
+
+	"github.com/gowww/i18n"
+	"golang.org/x/text/language"
+)
+
+// Date rule checks that value is a date in layout (see time.Parse).
+// Without layout, "2006-01-02" is used (like an HTML date input).
+func Date(layout string) Rule {
+	if layout == "" {
+		layout = "2006-01-02"
+	}
+	return layoutRule(layout, ErrNotDate)
+}
+
+// DateTime rule checks that value is a date and time in layout (see time.Parse).
+// Without layout, time.RFC3339 is used.
+func DateTime(layout string) Rule {
+	if layout == "" {
+		layout = time.RFC3339
+	}
+	return layoutRule(layout, ErrNotDateTime)
+}
+
+func layoutRule(layout string, errID *ErrorID) Rule {
+	return func(errs Errors, form *multipart.Form, key string) {
+		if form == nil {
+			return
+		}
+		for i, v := range form.Value[key] {
+			if _, err := time.Parse(layout, v); err != nil {
+				errs.Add(key, &Error{Error: errID, Indexes: []int{i}})
+			}
+		}
+	}
+}
+
+// A TimeBound returns a time limit from the current time of the check (see Clock).
+type TimeBound func(now time.Time) time.Time
+
+// At returns a TimeBound for the absolute time t.
+func At(t time.Time) TimeBound {
+	return func(time.Time) time.Time {
+		return t
+	}
+}
+
+// Now returns a TimeBound for the current time.
+func Now() TimeBound {
+	return FromNow(0)
+}
+
+// FromNow returns a TimeBound for the current time moved by d.
+func FromNow(d time.Duration) TimeBound {
+	return func(now time.Time) time.Time {
+		return now.Add(d)
+	}
+}
+
+// Today returns a TimeBound for the current day, at midnight UTC (like a parsed date without time).
+func Today() TimeBound {
+	return FromToday(0)
+}
+
+// FromToday returns a TimeBound for the current day moved by days, at midnight UTC.
+//
+// For example, to check a date within the next 90 days:
+//
+//	check.DateRange(check.Today(), check.FromToday(90))
+func FromToday(days int) TimeBound {
+	return func(now time.Time) time.Time {
+		return time.Date(now.Year(), now.Month(), now.Day()+days, 0, 0, 0, 0, time.UTC)
+	}
+}
+
+// Before rule checks that value is a date (see DateRange) before or equal to bound.
+//
+// For example, to refuse a date in the future:
+//
+//	check.Before(check.Today())
+func Before(bound TimeBound, layouts ...string) Rule {
+	return DateRange(nil, bound, layouts...)
+}
+
+// After rule checks that value is a date (see DateRange) after or equal to bound.
+//
+// For example, to refuse a date in the past:
+//
+//	check.After(check.Today())
+func After(bound TimeBound, layouts ...string) Rule {
+	return DateRange(bound, nil, layouts...)
+}
+
+// DateRange rule checks that value is a date between min and max, both included.
+// A nil bound is not checked.
+// Value is parsed with the first matching layout of layouts or, if there are none, of TimeLayouts.
+// It's the same for all the date rules taking layouts.
+//
+// For example, for a French date within the next 90 days:
+//
+//	check.DateRange(check.Today(), check.FromToday(90), "02/01/2006")
+func DateRange(min, max TimeBound, layouts ...string) Rule {
+	return func(errs Errors, form *multipart.Form, key string) {
+		if form == nil {
+			return
+		}
+		now := formState(form).now()
+		for i, v := range form.Value[key] {
+			t, ok := parseTime(v, layouts)
+			if !ok {
+				errs.Add(key, &Error{Error: ErrNotDate, Indexes: []int{i}})
+				continue
+			}
+			if max != nil {
+				if m := max(now); t.After(m) {
+					errs.Add(key, &Error{Error: ErrMaxDate, Args: []interface{}{TransDate(m)}, Indexes: []int{i}})
+					continue
+				}
+			}
+			if min != nil {
+				if m := min(now); t.Before(m) {
+					errs.Add(key, &Error{Error: ErrMinDate, Args: []interface{}{TransDate(m)}, Indexes: []int{i}})
+				}
+			}
+		}
+	}
+}
+
+// BeforeField rule checks that value is a date (see DateRange) before or equal to the date of the other key.
+// If the other key has no valid date, there is nothing to compare and the rule passes.
+func BeforeField(other string, layouts ...string) Rule {
+	return fieldRule(other, true, layouts)
+}
+
+// AfterField rule checks that value is a date (see DateRange) after or equal to the date of the other key.
+// If the other key has no valid date, there is nothing to compare and the rule passes.
+func AfterField(other string, layouts ...string) Rule {
+	return fieldRule(other, false, layouts)
+}
+
+func fieldRule(other string, before bool, layouts []string) Rule {
+	return func(errs Errors, form *multipart.Form, key string) {
+		if form == nil || len(form.Value[other]) == 0 {
+			return
+		}
+		bound, ok := parseTime(form.Value[other][0], layouts)
+		if !ok {
+			return
+		}
+		for i, v := range form.Value[key] {
+			t, ok := parseTime(v, layouts)
+			switch {
+			case !ok:
+				errs.Add(key, &Error{Error: ErrNotDate, Indexes: []int{i}})
+			case before && t.After(bound):
+				errs.Add(key, &Error{Error: ErrMaxDate, Args: []interface{}{TransDate(bound)}, Indexes: []int{i}})
+			case !before && t.Before(bound):
+				errs.Add(key, &Error{Error: ErrMinDate, Args: []interface{}{TransDate(bound)}, Indexes: []int{i}})
+			}
+		}
+	}
+}
+
+// MinAge rule checks that value is a birth date (see DateRange) of someone aged min years or more.
+func MinAge(min int, layouts ...string) Rule {
+	return ageRule(min, -1, layouts)
+}
+
+// MaxAge rule checks that value is a birth date (see DateRange) of someone aged max years or less.
+func MaxAge(max int, layouts ...string) Rule {
+	return ageRule(-1, max, layouts)
+}
+
+func ageRule(min, max int, layouts []string) Rule {
+	return func(errs Errors, form *multipart.Form, key string) {
+		if form == nil {
+			return
+		}
+		now := formState(form).now()
+		for i, v := range form.Value[key] {
+			t, ok := parseTime(v, layouts)
+			if !ok {
+				errs.Add(key, &Error{Error: ErrNotDate, Indexes: []int{i}})
+				continue
+			}
+			age := now.Year() - t.Year()
+			if now.Month() < t.Month() || now.Month() == t.Month() && now.Day() < t.Day() {
+				age--
+			}
+			if min >= 0 && age < min {
+				errs.Add(key, &Error{Error: ErrMinAge, Args: []interface{}{i18n.TransInt(min)}, Indexes: []int{i}})
+			} else if max >= 0 && age > max {
+				errs.Add(key, &Error{Error: ErrMaxAge, Args: []interface{}{i18n.TransInt(max)}, Indexes: []int{i}})
+			}
+		}
+	}
+}
+
+// parseTime parses s with the first matching layout of layouts or, if there are none, of TimeLayouts.
+func parseTime(s string, layouts []string) (time.Time, bool) {
+	if len(layouts) == 0 {
+		layouts = TimeLayouts
+	}
+	for _, layout := range layouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+var frenchMonths = [...]string{"janvier", "février", "mars", "avril", "mai", "juin", "juillet", "août", "septembre", "octobre", "novembre", "décembre"}
+
+// TransDate is a translatable argument representing a date, with its time if not midnight.
+type TransDate time.Time
+
+// T implements the i18n.Translatable interface.
+func (d TransDate) T(l language.Tag) string {
+	t := time.Time(d)
+	withTime := t.Hour() != 0 || t.Minute() != 0 || t.Second() != 0
+	switch l {
+	case language.French:
+		s := strconv.Itoa(t.Day()) + " " + frenchMonths[t.Month()-1] + " " + strconv.Itoa(t.Year())
+		if withTime {
+			s += " à " + t.Format("15:04")
+		}
+		return s
+	}
+	if withTime {
+		return t.Format("January 2, 2006 at 15:04")
+	}
+	return t.Format("January 2, 2006")
+}
+
+// String returns the date in the "2006-01-02" layout, or in time.RFC3339 with its time if not midnight.
+func (d TransDate) String() string {
+	t := time.Time(d)
+	if t.Hour() != 0 || t.Minute() != 0 || t.Second() != 0 {
+		return t.Format(time.RFC3339)
+	}
+	return t.Format("2006-01-02")
+}
@@ -0,0 +1,116 @@
+package check
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gowww/i18n"
+	"golang.org/x/text/language"
+)
+
+func TestDateRules(t *testing.T) {
+	now := time.Date(2020, 6, 15, 10, 30, 0, 0, time.UTC)
+	today := TransDate(time.Date(2020, 6, 15, 0, 0, 0, 0, time.UTC))
+	cases := []struct {
+		rule  Rule
+		value string
+		want  []*Error
+	}{
+		{Date(""), "2020-06-15", nil},
+		{Date(""), "15/06/2020", []*Error{{Error: ErrNotDate, Indexes: []int{0}}}},
+		{Date("02/01/2006"), "15/06/2020", nil},
+		{DateTime(""), "2020-06-15T10:30:00Z", nil},
+		{DateTime(""), "2020-06-15", []*Error{{Error: ErrNotDateTime, Indexes: []int{0}}}},
+		{Before(Today()), "2020-06-15", nil},
+		{Before(Today()), "2020-06-16", []*Error{{Error: ErrMaxDate, Args: []interface{}{today}, Indexes: []int{0}}}},
+		{Before(Now()), "2020-06-15T10:30", nil},
+		{Before(Now()), "2020-06-15T10:31", []*Error{{Error: ErrMaxDate, Args: []interface{}{TransDate(now)}, Indexes: []int{0}}}},
+		{After(Today()), "2020-06-14", []*Error{{Error: ErrMinDate, Args: []interface{}{today}, Indexes: []int{0}}}},
+		{After(FromNow(-time.Hour)), "2020-06-15T09:30:00Z", nil},
+		{After(Today()), "tomorrow", []*Error{{Error: ErrNotDate, Indexes: []int{0}}}},
+		{DateRange(Today(), FromToday(90)), "2020-09-13", nil},
+		{DateRange(Today(), FromToday(90)), "2020-09-14", []*Error{{Error: ErrMaxDate, Args: []interface{}{TransDate(time.Date(2020, 9, 13, 0, 0, 0, 0, time.UTC))}, Indexes: []int{0}}}},
+		{DateRange(At(time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)), nil), "1999-12-31", []*Error{{Error: ErrMinDate, Args: []interface{}{TransDate(time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC))}, Indexes: []int{0}}}},
+		{After(Today(), "02/01/2006"), "01/01/2999", nil},
+		{After(Today(), "02/01/2006"), "14/06/2020", []*Error{{Error: ErrMinDate, Args: []interface{}{today}, Indexes: []int{0}}}},
+		{After(Today(), "02/01/2006"), "2999-01-01", []*Error{{Error: ErrNotDate, Indexes: []int{0}}}},
+		{MinAge(18, "02/01/2006"), "15/06/2002", nil},
+		{MaxAge(17, "02/01/2006"), "15/06/2002", []*Error{{Error: ErrMaxAge, Args: []interface{}{i18n.TransInt(17)}, Indexes: []int{0}}}},
+		{MinAge(18), "2002-06-15", nil},
+		{MinAge(18), "2002-06-16", []*Error{{Error: ErrMinAge, Args: []interface{}{i18n.TransInt(18)}, Indexes: []int{0}}}},
+		{MaxAge(17), "2002-06-16", nil},
+		{MaxAge(17), "2002-06-15", []*Error{{Error: ErrMaxAge, Args: []interface{}{i18n.TransInt(17)}, Indexes: []int{0}}}},
+	}
+	for i, c := range cases {
+		errs := Checker{"date": {c.rule}}.CheckValues(map[string][]string{"date": {c.value}}, Clock(func() time.Time { return now }))
+		if !reflect.DeepEqual(c.want, errs["date"]) {
+			t.Errorf("case %d:\nwant %v\ngot  %v", i, c.want, errs["date"])
+		}
+	}
+}
+
+func TestFieldDateRules(t *testing.T) {
+	checker := Checker{
+		"start": {BeforeField("end")},
+		"end":   {AfterField("start")},
+	}
+	cases := []struct {
+		values map[string][]string
+		want   Errors
+	}{
+		{map[string][]string{"start": {"2020-06-15"}, "end": {"2020-06-15"}}, Errors{}},
+		{map[string][]string{"start": {"2020-06-15"}}, Errors{}},
+		{map[string][]string{"start": {"2020-06-15"}, "end": {"soon"}}, Errors{"end": {{Error: ErrNotDate, Indexes: []int{0}}}}},
+		{map[string][]string{"start": {"2020-06-16"}, "end": {"2020-06-15"}}, Errors{
+			"start": {{Error: ErrMaxDate, Args: []interface{}{TransDate(time.Date(2020, 6, 15, 0, 0, 0, 0, time.UTC))}, Indexes: []int{0}}},
+			"end":   {{Error: ErrMinDate, Args: []interface{}{TransDate(time.Date(2020, 6, 16, 0, 0, 0, 0, time.UTC))}, Indexes: []int{0}}},
+		}},
+	}
+	for i, c := range cases {
+		if got := checker.CheckValues(c.values); !reflect.DeepEqual(c.want, got) {
+			t.Errorf("case %d:\nwant %v\ngot  %v", i, c.want, got)
+		}
+	}
+}
+
+func TestDateRulesExpressions(t *testing.T) {
+	c, err := ParseChecker(map[string]string{
+		"start": "after:today,02/01/2006",
+		"end":   "afterfield:start,02/01/2006",
+		"birth": "minage:18,02/01/2006",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2020, 6, 15, 0, 0, 0, 0, time.UTC)
+	got := c.CheckValues(map[string][]string{"start": {"01/01/2999"}, "end": {"31/12/2998"}, "birth": {"16/06/2002"}}, Clock(func() time.Time { return now }))
+	want := Errors{
+		"end":   {{Error: ErrMinDate, Args: []interface{}{TransDate(time.Date(2999, 1, 1, 0, 0, 0, 0, time.UTC))}, Indexes: []int{0}}},
+		"birth": {{Error: ErrMinAge, Args: []interface{}{"18"}, Indexes: []int{0}}},
+	}
+	if !reflect.DeepEqual(want.StringMap(), got.StringMap()) {
+		t.Errorf("ParseChecker:\nwant %v\ngot  %v", want, got)
+	}
+}
+
+func TestTransDate(t *testing.T) {
+	cases := []struct {
+		date     TransDate
+		language language.Tag
+		want     string
+	}{
+		{TransDate(time.Date(2020, 6, 15, 0, 0, 0, 0, time.UTC)), language.English, "June 15, 2020"},
+		{TransDate(time.Date(2020, 6, 15, 0, 0, 0, 0, time.UTC)), language.French, "15 juin 2020"},
+		{TransDate(time.Date(2020, 8, 1, 9, 5, 0, 0, time.UTC)), language.English, "August 1, 2020 at 09:05"},
+		{TransDate(time.Date(2020, 8, 1, 9, 5, 0, 0, time.UTC)), language.French, "1 août 2020 à 09:05"},
+	}
+	for _, c := range cases {
+		if got := c.date.T(c.language); got != c.want {
+			t.Errorf("TransDate.T(%v):\nwant %q\ngot  %q", c.language, c.want, got)
+		}
+	}
+	if got := TransDate(time.Date(2020, 6, 15, 0, 0, 0, 0, time.UTC)).String(); got != "2020-06-15" {
+		t.Errorf("TransDate.String:\nwant %q\ngot  %q", "2020-06-15", got)
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/gowww/i18n"
@@ -23,10 +25,18 @@ var (
 		language.English: "Only these file types are accepted: %v.",
 		language.French:  "Seul ces types de fichier sont acceptés: %v.",
 	}}
+	ErrBadRequest = &ErrorID{ID: "badRequest", Locales: map[language.Tag]string{
+		language.English: "The request could not be read.",
+		language.French:  "La requête n'a pas pu être lue.",
+	}}
 	ErrIllogical = &ErrorID{ID: "illogical", Locales: map[language.Tag]string{
 		language.English: "This value is illogical.",
 		language.French:  "Cette valeur est illogique.",
 	}}
+	ErrInternal = &ErrorID{ID: "internal", Locales: map[language.Tag]string{
+		language.English: "This value could not be checked.",
+		language.French:  "Cette valeur n'a pas pu être vérifiée.",
+	}}
 	ErrInvalid = &ErrorID{ID: "invalid", Locales: map[language.Tag]string{
 		language.English: "This value is invalid.",
 		language.French:  "Cette valeur est invalide.",
@@ -35,6 +45,22 @@ var (
 		language.English: "The maximal value is %v.",
 		language.French:  "La valeur maximale est de %v",
 	}}
+	ErrMaxAge = &ErrorID{ID: "maxAge", Locales: map[language.Tag]string{
+		language.English: "The maximal age is %v years.",
+		language.French:  "L'âge maximal est de %v ans.",
+	}}
+	ErrMaxBodySize = &ErrorID{ID: "maxBodySize", Locales: map[language.Tag]string{
+		language.English: "The request is too large.",
+		language.French:  "La requête est trop volumineuse.",
+	}}
+	ErrMaxCount = &ErrorID{ID: "maxCount", Locales: map[language.Tag]string{
+		language.English: "There are more than %v values.",
+		language.French:  "Il y a plus de %v valeurs.",
+	}}
+	ErrMaxDate = &ErrorID{ID: "maxDate", Locales: map[language.Tag]string{
+		language.English: "The date must be %v or before.",
+		language.French:  "La date doit être le %v ou avant.",
+	}}
 	ErrMaxFileSize = &ErrorID{ID: "maxFileSize", Locales: map[language.Tag]string{
 		language.English: "File size is over %v.",
 		language.French:  "La taille du fichier dépasse %v.",
@@ -43,10 +69,26 @@ var (
 		language.English: "The value exceeds %v characters.",
 		language.French:  "La valeur dépasse %v caractères.",
 	}}
+	ErrMaxTotalFileSize = &ErrorID{ID: "maxTotalFileSize", Locales: map[language.Tag]string{
+		language.English: "Total size of files is over %v.",
+		language.French:  "La taille totale des fichiers dépasse %v.",
+	}}
 	ErrMin = &ErrorID{ID: "min", Locales: map[language.Tag]string{
 		language.English: "The minimal value is %v.",
 		language.French:  "La valeur minimale est de %v",
 	}}
+	ErrMinAge = &ErrorID{ID: "minAge", Locales: map[language.Tag]string{
+		language.English: "The minimal age is %v years.",
+		language.French:  "L'âge minimal est de %v ans.",
+	}}
+	ErrMinCount = &ErrorID{ID: "minCount", Locales: map[language.Tag]string{
+		language.English: "There must be at least %v values.",
+		language.French:  "Il doit y avoir au moins %v valeurs.",
+	}}
+	ErrMinDate = &ErrorID{ID: "minDate", Locales: map[language.Tag]string{
+		language.English: "The date must be %v or after.",
+		language.French:  "La date doit être le %v ou après.",
+	}}
 	ErrMinFileSize = &ErrorID{ID: "minFileSize", Locales: map[language.Tag]string{
 		language.English: "File size must be at least %v.",
 		language.French:  "La taille du fichier doit être d'au moins %v.",
@@ -55,6 +97,10 @@ var (
 		language.English: "The value must have more than %v characters.",
 		language.French:  "La veleur doit comporter au moins %v caractères.",
 	}}
+	ErrNotAllowed = &ErrorID{ID: "notAllowed", Locales: map[language.Tag]string{
+		language.English: "This value is not allowed.",
+		language.French:  "Cette valeur n'est pas autorisée.",
+	}}
 	ErrNotAlpha = &ErrorID{ID: "notAlpha", Locales: map[language.Tag]string{
 		language.English: "It's not a letters-only string.",
 		language.French:  "Ce n'est pas une suite de lettres (uniquement).",
@@ -63,6 +109,18 @@ var (
 		language.English: "It's not an alphanumeric-only string.",
 		language.French:  "Ce n'est pas une suite alphanumérique (uniquement).",
 	}}
+	ErrNotDate = &ErrorID{ID: "notDate", Locales: map[language.Tag]string{
+		language.English: "It's not a date.",
+		language.French:  "Ce n'est pas une date.",
+	}}
+	ErrNotDateTime = &ErrorID{ID: "notDateTime", Locales: map[language.Tag]string{
+		language.English: "It's not a date and time.",
+		language.French:  "Ce n'est pas une date et heure.",
+	}}
+	ErrNotDistinct = &ErrorID{ID: "notDistinct", Locales: map[language.Tag]string{
+		language.English: "Values must be distinct.",
+		language.French:  "Les valeurs doivent être distinctes.",
+	}}
 	ErrNotEmail = &ErrorID{ID: "notEmail", Locales: map[language.Tag]string{
 		language.English: "It's not an email.",
 		language.French:  "Ce n'est pas un e-mail.",
@@ -75,6 +133,10 @@ var (
 		language.English: "It's not an image.",
 		language.French:  "Ce n'est pas une image.",
 	}}
+	ErrNotIn = &ErrorID{ID: "notIn", Locales: map[language.Tag]string{
+		language.English: "Only these values are accepted: %v.",
+		language.French:  "Seules ces valeurs sont acceptées: %v.",
+	}}
 	ErrNotInteger = &ErrorID{ID: "notInteger", Locales: map[language.Tag]string{
 		language.English: "It's not a integer number.",
 		language.French:  "Ce n'est pas un nombre entier.",
@@ -111,6 +173,10 @@ var (
 		language.English: "A value is required.",
 		language.French:  "Une valeur est requise.",
 	}}
+	ErrUnknownField = &ErrorID{ID: "unknownField", Locales: map[language.Tag]string{
+		language.English: "This field is not allowed.",
+		language.French:  "Ce champ n'est pas autorisé.",
+	}}
 	ErrWrongPassword = &ErrorID{ID: "password", Locales: map[language.Tag]string{
 		language.English: "The password is wrong.",
 		language.French:  "Le mot de passe est incorrect.",
@@ -118,9 +184,15 @@ var (
 )
 
 // An Error is a checking error from a rule, with rule's variables.
+// For an ErrInternal error, Cause is the failure that prevented the rule from making its check.
+//
+// When a rule checks the values (or files) of a key one by one, Indexes contains the positions of the failing ones, in ascending order.
+// See Errors.Indexed to get an error for each of them.
 type Error struct {
-	Error *ErrorID
-	Args  []interface{}
+	Error   *ErrorID
+	Args    []interface{}
+	Cause   error
+	Indexes []int
 }
 
 // T returns an Error translation from an i18n.Translator (from key "error" + title case error ID, like "errorNotImage").
@@ -169,14 +241,23 @@ func (e *Error) String() string {
 type Errors map[string][]*Error
 
 // Add appends a failed validation Error to key.
+// If key already has an error with the same ErrorID, their indexes (if any) are merged.
 func (e Errors) Add(key string, err *Error) {
 	if err.Error == ErrRequired { // ErrRequired is always lonely.
 		e[key] = []*Error{{Error: ErrRequired}}
 		return
 	}
 	if checkErrors := e[key]; len(checkErrors) > 0 {
-		for _, ce := range checkErrors {
-			if ce.Error == err.Error || ce.Error == ErrRequired { // No duplicated errors and no other errors when ErrRequired exists.
+		for i, ce := range checkErrors {
+			if ce.Error == ErrRequired { // No other errors when ErrRequired exists.
+				return
+			}
+			if ce.Error == err.Error { // No duplicated errors.
+				if len(ce.Indexes) > 0 && len(err.Indexes) > 0 { // An error for the whole key stays so.
+					merged := *ce // Errors can be shared with other maps (see Merge), so they are never changed.
+					merged.Indexes = mergeIndexes(ce.Indexes, err.Indexes)
+					checkErrors[i] = &merged
+				}
 				return
 			}
 		}
@@ -188,6 +269,67 @@ func (e Errors) Add(key string, err *Error) {
 	}
 }
 
+// mergeIndexes returns the sorted union of a and b.
+func mergeIndexes(a, b []int) []int {
+	m := append(append(make([]int, 0, len(a)+len(b)), a...), b...)
+	sort.Ints(m)
+	j := 0
+	for i := range m {
+		if i == 0 || m[i] != m[j-1] {
+			m[j] = m[i]
+			j++
+		}
+	}
+	return m[:j]
+}
+
+// Fail sets an ErrInternal error for key, when a rule fails to make its check because of err (like a database outage).
+// Checker.CheckE returns this failure separately from the other errors.
+func (e Errors) Fail(key string, err error) {
+	e.Add(key, &Error{Error: ErrInternal, Cause: err})
+}
+
+// failure removes the ErrInternal errors and returns the first one (by key order) as a *RuleError.
+func (e Errors) failure() error {
+	var rerr *RuleError
+	for k, errs := range e {
+		for i := 0; i < len(errs); i++ {
+			if errs[i].Error != ErrInternal {
+				continue
+			}
+			if rerr == nil || k < rerr.Key {
+				rerr = &RuleError{Key: k, Err: errs[i].Cause}
+			}
+			errs = append(errs[:i], errs[i+1:]...)
+			i--
+		}
+		if len(errs) == 0 {
+			delete(e, k)
+		} else {
+			e[k] = errs
+		}
+	}
+	if rerr == nil {
+		return nil
+	}
+	return rerr
+}
+
+// A RuleError is the failure of a rule that could not make its check for a key.
+type RuleError struct {
+	Key string
+	Err error
+}
+
+func (e *RuleError) Error() string {
+	return fmt.Sprintf("check: rule failed for key %q: %v", e.Key, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *RuleError) Unwrap() error {
+	return e.Err
+}
+
 // Empty tells if the errors map contains no keys.
 func (e Errors) Empty() bool {
 	return len(e) == 0
@@ -233,6 +375,30 @@ func (e Errors) Merge(e2 Errors) {
 	}
 }
 
+// Indexed returns a copy of the errors where each error with indexes (see Error) is set for the indexed keys instead of the whole key.
+// So if the third value of "tags" is too long, the error is set for key "tags[2]".
+//
+// It's useful for a form with repeated inputs, to show the error next to the failing one:
+//
+//	errsjs, _ := json.Marshal(errs.Indexed().JSON())
+func (e Errors) Indexed() Errors {
+	ie := make(Errors, len(e))
+	for k, errs := range e {
+		for _, err := range errs {
+			if len(err.Indexes) == 0 {
+				ie.Add(k, err)
+				continue
+			}
+			for _, i := range err.Indexes {
+				ierr := *err
+				ierr.Indexes = nil
+				ie.Add(k+"["+strconv.Itoa(i)+"]", &ierr)
+			}
+		}
+	}
+	return ie
+}
+
 // StringMap returns the errors as a readable string map.
 func (e Errors) StringMap() map[string][]string {
 	m := make(map[string][]string, len(e))
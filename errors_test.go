@@ -1,5 +1,13 @@
 package check
 
+import (
+	"mime/multipart"
+	"reflect"
+	"testing"
+
+	"github.com/gowww/i18n"
+)
+
 /*
 import (
 	"reflect"
@@ -62,3 +70,71 @@ func TestErrorsJSON(t *testing.T) {
 	}
 }
 */
+
+func TestErrorsFail(t *testing.T) {
+	c := Checker{
+		"email":   {Email},
+		"picture": {Required, Image},
+	}
+	form := &multipart.Form{
+		Value: map[string][]string{"email": {"foo"}},
+		File:  map[string][]*multipart.FileHeader{"picture": {{Filename: "missing.png"}}},
+	}
+
+	errs := c.Check(form)
+	if e := errs.First("picture"); e == nil || e.Error != ErrInternal || e.Cause == nil {
+		t.Errorf("Checker.Check: want %s error with cause for failed rule, got %v", ErrInternal.ID, errs)
+	}
+
+	errs, err := c.CheckE(form)
+	rerr, ok := err.(*RuleError)
+	if !ok || rerr.Key != "picture" {
+		t.Errorf("Checker.CheckE: want *RuleError for key %q, got %v", "picture", err)
+	}
+	want := Errors{"email": {{Error: ErrNotEmail, Indexes: []int{0}}}}
+	if !reflect.DeepEqual(want, errs) {
+		t.Errorf("Checker.CheckE:\nwant %v\ngot  %v", want, errs)
+	}
+}
+
+func TestErrorsIndexed(t *testing.T) {
+	c := Checker{
+		"tags":   {MaxLen(3), Alpha},
+		"scores": {Each(Integer, Max(10))},
+		"email":  {Required},
+	}
+	errs := c.CheckValues(map[string][]string{
+		"tags":   {"foo", "foobar", "a1", "barbaz"},
+		"scores": {"1", "x", "11"},
+	})
+	want := Errors{
+		"tags": {
+			{Error: ErrMaxLen, Args: []interface{}{i18n.TransInt(3)}, Indexes: []int{1, 3}},
+			{Error: ErrNotAlpha, Indexes: []int{2}},
+		},
+		"scores": {
+			{Error: ErrNotInteger, Indexes: []int{1}},
+			{Error: ErrNotNumber, Indexes: []int{1}},
+			{Error: ErrMax, Args: []interface{}{i18n.TransInt(10)}, Indexes: []int{2}},
+		},
+		"email": {{Error: ErrRequired}},
+	}
+	if !reflect.DeepEqual(want, errs) {
+		t.Errorf("Checker.Check:\nwant %v\ngot  %v", want, errs)
+	}
+
+	wantIndexed := map[string][]string{
+		"tags[1]":   {"maxLen:3"},
+		"tags[2]":   {"notAlpha"},
+		"tags[3]":   {"maxLen:3"},
+		"scores[1]": {"notInteger", "notNumber"},
+		"scores[2]": {"max:10"},
+		"email":     {"required"},
+	}
+	if got := errs.Indexed().StringMap(); !reflect.DeepEqual(wantIndexed, got) {
+		t.Errorf("Errors.Indexed:\nwant %v\ngot  %v", wantIndexed, got)
+	}
+	if !reflect.DeepEqual(want, errs) {
+		t.Errorf("Errors.Indexed: original errors changed:\nwant %v\ngot  %v", want, errs)
+	}
+}
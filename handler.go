@@ -0,0 +1,140 @@
+package check
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"html"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/gowww/i18n"
+)
+
+type contextKey int
+
+const contextKeyForm contextKey = iota
+
+// A Responder writes the response of a Handler when the check has errors.
+type Responder func(w http.ResponseWriter, r *http.Request, errs Errors)
+
+// Respond sets the responder used by a Handler when the check has errors.
+// By default, RespondJSON is used for a request accepting or sending JSON, and RespondHTML otherwise.
+func Respond(f Responder) Option {
+	return func(o *options) {
+		o.responder = f
+	}
+}
+
+// Handler returns a handler making the check for each request (like CheckRequestContext) before calling next.
+//
+// When the check has errors, the response is written by the responder (see Respond) with a 422 status and next is not called.
+// When the request cannot be parsed, the status is 413 (for ErrRequestTooLarge) or 400.
+// When a rule fails to make its check, the status is 500.
+//
+// Otherwise, next is called and the checked form, with the values changed by the sanitizers, is available with RequestForm:
+//
+//	http.Handle("/users", userChecker.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+//		form := check.RequestForm(r)
+//		// ...
+//	})))
+func (c Checker) Handler(next http.Handler, opts ...Option) http.Handler {
+	return c.Ordered().Handler(next, opts...)
+}
+
+// Handler works like Checker.Handler.
+func (c OrderedChecker) Handler(next http.Handler, opts ...Option) http.Handler {
+	responder := newOptions(opts).responder
+	if responder == nil {
+		responder = respondDefault
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		form, errs, err := requestForm(r, c, opts)
+		if err != nil {
+			status := http.StatusBadRequest
+			if errors.Is(err, ErrRequestTooLarge) {
+				status = http.StatusRequestEntityTooLarge
+			}
+			http.Error(w, http.StatusText(status), status)
+			return
+		}
+		if errs != nil { // Streamed upload aborted.
+			responder(w, r, errs)
+			return
+		}
+		form, errs, err = c.check(r.Context(), form, opts)
+		if err != nil { // Request context is done, so no one is waiting for a response.
+			return
+		}
+		if errs.failure() != nil {
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+		if errs.NotEmpty() {
+			responder(w, r, errs)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), contextKeyForm, form)))
+	})
+}
+
+// RequestForm returns the form checked by a Handler for r, with the values changed by the sanitizers.
+// If r has not been checked by a Handler, nil.
+func RequestForm(r *http.Request) *multipart.Form {
+	form, _ := r.Context().Value(contextKeyForm).(*multipart.Form)
+	return form
+}
+
+// RespondJSON writes errs translated by the request translator (see i18n.RequestTranslator), as JSON under the "errors" key, with a 422 status.
+func RespondJSON(w http.ResponseWriter, r *http.Request, errs Errors) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusUnprocessableEntity)
+	json.NewEncoder(w).Encode(map[string]TranslatedErrors{"errors": errs.T(i18n.RequestTranslator(r))})
+}
+
+// RespondHTML writes errs translated by the request translator (see i18n.RequestTranslator), as an HTML list, with a 422 status.
+func RespondHTML(w http.ResponseWriter, r *http.Request, errs Errors) {
+	te := errs.T(i18n.RequestTranslator(r))
+	keys := make([]string, 0, len(te))
+	for k := range te {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusUnprocessableEntity)
+	b := new(strings.Builder)
+	b.WriteString("<ul>\n")
+	for _, k := range keys {
+		for _, s := range te[k] {
+			b.WriteString("<li><strong>" + html.EscapeString(k) + "</strong>: " + html.EscapeString(s) + "</li>\n")
+		}
+	}
+	b.WriteString("</ul>\n")
+	w.Write([]byte(b.String()))
+}
+
+// respondDefault uses RespondJSON for a request accepting or sending JSON, and RespondHTML otherwise.
+func respondDefault(w http.ResponseWriter, r *http.Request, errs Errors) {
+	if isJSONRequest(r) || acceptsJSON(r) {
+		RespondJSON(w, r, errs)
+		return
+	}
+	RespondHTML(w, r, errs)
+}
+
+// acceptsJSON tells if the Accept header of r prefers JSON to HTML.
+func acceptsJSON(r *http.Request) bool {
+	for _, s := range strings.Split(r.Header.Get("Accept"), ",") {
+		mt, _, _ := mime.ParseMediaType(strings.TrimSpace(s))
+		switch mt {
+		case "application/json":
+			return true
+		case "text/html":
+			return false
+		}
+	}
+	return false
+}
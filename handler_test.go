@@ -0,0 +1,59 @@
+package check
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHandler(t *testing.T) {
+	c := Checker{"email": {Trim, Required, Email}}
+	var got string
+	h := c.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = RequestForm(r).Value["email"][0]
+	}), MaxBodySize(1000))
+
+	cases := []struct {
+		body        string
+		contentType string
+		accept      string
+		wantStatus  int
+		wantBody    string
+	}{
+		{"email=+foo@example.com+", "application/x-www-form-urlencoded", "", http.StatusOK, ""},
+		{"email=foo", "application/x-www-form-urlencoded", "text/html", http.StatusUnprocessableEntity, "<li><strong>email</strong>: It&#39;s not an email.</li>"},
+		{"email=foo", "application/x-www-form-urlencoded", "application/json", http.StatusUnprocessableEntity, `{"errors":{"email":["It's not an email."]}}`},
+		{`{"email":""}`, "application/json", "", http.StatusUnprocessableEntity, `{"errors":{"email":["A value is required."]}}`},
+		{`{"email":`, "application/json", "", http.StatusBadRequest, ""},
+		{"email=" + strings.Repeat("a", 1000), "application/x-www-form-urlencoded", "", http.StatusRequestEntityTooLarge, ""},
+	}
+	for _, c := range cases {
+		got = ""
+		r := httptest.NewRequest("POST", "/", strings.NewReader(c.body))
+		r.Header.Set("Content-Type", c.contentType)
+		r.Header.Set("Accept", c.accept)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		if w.Code != c.wantStatus {
+			t.Errorf("Handler(%q): want status %d, got %d", c.body, c.wantStatus, w.Code)
+		}
+		if !strings.Contains(w.Body.String(), c.wantBody) {
+			t.Errorf("Handler(%q): want body containing %q, got %q", c.body, c.wantBody, w.Body.String())
+		}
+		if c.wantStatus == http.StatusOK && got != "foo@example.com" {
+			t.Errorf("Handler(%q): want cleaned form value %q, got %q", c.body, "foo@example.com", got)
+		}
+	}
+}
+
+func TestHandlerRespond(t *testing.T) {
+	h := Checker{"email": {Required}}.Handler(http.NotFoundHandler(), Respond(func(w http.ResponseWriter, r *http.Request, errs Errors) {
+		w.WriteHeader(http.StatusTeapot)
+	}))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+	if w.Code != http.StatusTeapot {
+		t.Errorf("Handler with Respond: want status %d, got %d", http.StatusTeapot, w.Code)
+	}
+}
@@ -0,0 +1,84 @@
+package check
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"strconv"
+)
+
+var errNoJSONObject = errors.New("check: JSON data is not an object")
+
+// CheckJSON makes the check for a JSON object read from r and returns errors.
+// A decoding error is returned if data is not a valid JSON object.
+//
+// The object is flattened to a values map:
+//
+//   - Nested object keys are joined by a dot, like "address.city".
+//   - Arrays of strings, numbers and booleans are multiple values for the same key, like repeated form fields.
+//   - Objects and arrays inside arrays are addressed by their index, like "items.0.sku".
+//   - Numbers are kept as written in JSON ("12.50" stays "12.50"), booleans are "true" or "false" and null is an empty value.
+//
+// Result is guaranteed to be non-nil.
+func (c Checker) CheckJSON(r io.Reader, opts ...Option) (Errors, error) {
+	values, err := jsonValues(r)
+	if err != nil {
+		return make(Errors), err
+	}
+	return c.CheckValues(values, opts...), nil
+}
+
+// jsonValues decodes a JSON object from r and flattens it into a values map.
+func jsonValues(r io.Reader) (map[string][]string, error) {
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	obj, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, errNoJSONObject
+	}
+	values := make(map[string][]string)
+	for k, v := range obj {
+		flattenJSON(values, k, v)
+	}
+	return values, nil
+}
+
+func flattenJSON(values map[string][]string, key string, v interface{}) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, vv := range v {
+			flattenJSON(values, key+"."+k, vv)
+		}
+	case []interface{}:
+		if values[key] == nil {
+			values[key] = []string{}
+		}
+		for i, vv := range v {
+			switch vv.(type) {
+			case map[string]interface{}, []interface{}:
+				flattenJSON(values, key+"."+strconv.Itoa(i), vv)
+			default:
+				values[key] = append(values[key], jsonString(vv))
+			}
+		}
+	default:
+		values[key] = append(values[key], jsonString(v))
+	}
+}
+
+// jsonString returns the string representation of a JSON scalar.
+func jsonString(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case bool:
+		return strconv.FormatBool(v)
+	}
+	return "" // null
+}
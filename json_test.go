@@ -0,0 +1,104 @@
+package check
+
+import (
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var testJSON = `{
+	"email": "foo@example.com",
+	"stars": 4.5,
+	"admin": false,
+	"note": null,
+	"tags": ["a", 1, true],
+	"address": {"city": "Paris", "zip": 75000},
+	"items": [{"sku": "A1", "quantity": 2}, {"sku": "B2"}]
+}`
+
+func TestJSONValues(t *testing.T) {
+	want := map[string][]string{
+		"email":            {"foo@example.com"},
+		"stars":            {"4.5"},
+		"admin":            {"false"},
+		"note":             {""},
+		"tags":             {"a", "1", "true"},
+		"address.city":     {"Paris"},
+		"address.zip":      {"75000"},
+		"items":            {},
+		"items.0.sku":      {"A1"},
+		"items.0.quantity": {"2"},
+		"items.1.sku":      {"B2"},
+	}
+	got, err := jsonValues(strings.NewReader(testJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("jsonValues:\nwant %v\ngot  %v", want, got)
+	}
+}
+
+func TestCheckerCheckJSON(t *testing.T) {
+	c := Checker{
+		"email":            {Required, Email},
+		"note":             {Required},
+		"address.city":     {Alpha},
+		"items.1.quantity": {Required, Integer},
+	}
+	want := Errors{
+		"note":             {{Error: ErrRequired}},
+		"items.1.quantity": {{Error: ErrRequired}},
+	}
+	got, err := c.CheckJSON(strings.NewReader(testJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("Checker.CheckJSON:\nwant %v\ngot  %v", want, got)
+	}
+
+	if _, err = c.CheckJSON(strings.NewReader(`["foo"]`)); err == nil {
+		t.Error("Checker.CheckJSON: want error for non-object data")
+	}
+}
+
+func TestCheckerCheckJSONStrict(t *testing.T) {
+	c := Checker{"items[*].sku": {Alpha}}
+	for _, data := range []string{`{"items": [{"sku": "a"}]}`, `{"items": []}`} {
+		got, err := c.CheckJSON(strings.NewReader(data), Strict())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.NotEmpty() {
+			t.Errorf("Checker.CheckJSON(%s): want no errors, got %v", data, got)
+		}
+	}
+	if got, _ := (Checker{"address.city": {Alpha}}).CheckJSON(strings.NewReader(`{"address": {"city": "Paris"}}`), Strict()); got.NotEmpty() {
+		t.Errorf("Checker.CheckJSON: want no errors, got %v", got)
+	}
+	got := (Checker{"address.city": {Alpha}}).CheckValues(map[string][]string{"address": {"is_admin"}, "address.city": {"Paris"}}, Strict())
+	if want := (Errors{"address": {{Error: ErrUnknownField}}}); !reflect.DeepEqual(want, got) {
+		t.Errorf("Checker.CheckValues:\nwant %v\ngot  %v", want, got)
+	}
+
+	want := Errors{"items.0.price": {{Error: ErrUnknownField}}}
+	if got, _ := c.CheckJSON(strings.NewReader(`{"items": [{"sku": "a", "price": 1}]}`), Strict()); !reflect.DeepEqual(want, got) {
+		t.Errorf("Checker.CheckJSON:\nwant %v\ngot  %v", want, got)
+	}
+}
+
+func TestCheckerCheckRequestJSON(t *testing.T) {
+	r, _ := http.NewRequest("POST", "/?page=a", strings.NewReader(testJSON))
+	r.Header.Set("Content-Type", "application/json; charset=utf-8")
+	want := Errors{"page": {{Error: ErrNotInteger, Indexes: []int{0}}}}
+	got := Checker{"email": {Required, Email}, "page": {Integer}}.CheckRequest(r)
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("Checker.CheckRequest:\nwant %v\ngot  %v", want, got)
+	}
+	if b, _ := ioutil.ReadAll(r.Body); string(b) != testJSON {
+		t.Errorf("Checker.CheckRequest: body not reset, got %q", b)
+	}
+}
@@ -0,0 +1,122 @@
+package check
+
+import (
+	"mime/multipart"
+	"sort"
+	"strings"
+)
+
+// formKeys returns the form keys matched by a Checker key.
+//
+// A key is a path whose segments are separated by dots or enclosed in brackets, so "items[3].quantity" and "items.3.quantity" are the same.
+// A "*" segment matches any segment, like "items[*].quantity" or "items.*.quantity".
+//
+// When no form key matches, the Checker key itself is returned so its rules (like Required) are still applied.
+func formKeys(form *multipart.Form, key string) []string {
+	if form == nil || formHas(form, key) {
+		return []string{key}
+	}
+	pattern := splitKey(key)
+	var keys []string
+	for _, k := range formAllKeys(form) {
+		if matchKey(pattern, splitKey(k)) {
+			keys = append(keys, k)
+		}
+	}
+	if len(keys) == 0 {
+		return []string{key}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// formHas tells if key exists in form values or files.
+func formHas(form *multipart.Form, key string) bool {
+	if form == nil {
+		return false
+	}
+	if _, ok := form.Value[key]; ok {
+		return true
+	}
+	_, ok := form.File[key]
+	return ok
+}
+
+// formAllKeys returns all the keys of form values and files.
+func formAllKeys(form *multipart.Form) []string {
+	keys := make([]string, 0, len(form.Value)+len(form.File))
+	for k := range form.Value {
+		keys = append(keys, k)
+	}
+	for k := range form.File {
+		if _, ok := form.Value[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
+
+// splitKey returns the path segments of key.
+func splitKey(key string) []string {
+	return strings.FieldsFunc(key, func(r rune) bool {
+		return r == '.' || r == '[' || r == ']'
+	})
+}
+
+// matchKey tells if path matches pattern, where a "*" segment matches any segment.
+func matchKey(pattern, path []string) bool {
+	if len(pattern) != len(path) {
+		return false
+	}
+	for i := range pattern {
+		if pattern[i] != "*" && pattern[i] != path[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// matchAnyKey tells if key matches one of the patterns (see formKeys).
+func matchAnyKey(patterns []string, key string) bool {
+	path := splitKey(key)
+	for _, pattern := range patterns {
+		if pattern == key || matchKey(splitKey(pattern), path) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchAnyKeyPath tells if key, or one of its parent keys, matches one of the patterns (see formKeys).
+// So "address" matches "address.city" and "items[*]" matches "items[0].quantity".
+func matchAnyKeyPath(patterns []string, key string) bool {
+	path := splitKey(key)
+	for _, pattern := range patterns {
+		if pattern == key {
+			return true
+		}
+		pp := splitKey(pattern)
+		if len(pp) <= len(path) && matchKey(pp, path[:len(pp)]) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchAnyParentKey tells if key is a parent key of one of the patterns (see formKeys).
+// So "items" is a parent key of "items[*].sku" and "items.0.sku".
+func matchAnyParentKey(patterns []string, key string) bool {
+	path := splitKey(key)
+	for _, pattern := range patterns {
+		pp := splitKey(pattern)
+		if len(pp) > len(path) && matchKey(pp[:len(path)], path) {
+			return true
+		}
+	}
+	return false
+}
+
+// formPresent tells if key, or one of its nested keys (like "address.city" for "address"), exists in form values or files.
+func formPresent(form *multipart.Form, key string) bool {
+	return formHas(form, key) || formHasPrefix(form, key+".") || formHasPrefix(form, key+"[")
+}
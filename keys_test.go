@@ -0,0 +1,57 @@
+package check
+
+import (
+	"mime/multipart"
+	"reflect"
+	"testing"
+)
+
+func TestFormKeys(t *testing.T) {
+	form := &multipart.Form{
+		Value: map[string][]string{
+			"name":                {"foo"},
+			"items[0].quantity":   {"1"},
+			"items[1].quantity":   {"2"},
+			"items.10.quantity":   {"3"},
+			"items[0].sku":        {"A1"},
+			"items[0][sub].price": {"4"},
+		},
+		File: map[string][]*multipart.FileHeader{
+			"docs.0": nil,
+		},
+	}
+	cases := []struct {
+		key  string
+		want []string
+	}{
+		{"name", []string{"name"}},
+		{"missing", []string{"missing"}},
+		{"items[*].quantity", []string{"items.10.quantity", "items[0].quantity", "items[1].quantity"}},
+		{"items.*.quantity", []string{"items.10.quantity", "items[0].quantity", "items[1].quantity"}},
+		{"items.0.sku", []string{"items[0].sku"}},
+		{"items[*][*].price", []string{"items[0][sub].price"}},
+		{"docs[*]", []string{"docs.0"}},
+		{"items[*].missing", []string{"items[*].missing"}},
+	}
+	for _, c := range cases {
+		if got := formKeys(form, c.key); !reflect.DeepEqual(c.want, got) {
+			t.Errorf("formKeys(%q): want %v, got %v", c.key, c.want, got)
+		}
+	}
+}
+
+func TestCheckerCheckWildcard(t *testing.T) {
+	c := Checker{"items[*].quantity": {Required, Integer, Range(1, 99)}}
+	got := c.CheckValues(map[string][]string{
+		"items[0].quantity": {"1"},
+		"items[1].quantity": {"100"},
+		"items[3].quantity": {"a"},
+	})
+	want := Errors{
+		"items[1].quantity": {{Error: ErrMax, Args: []interface{}{"99"}}},
+		"items[3].quantity": {{Error: ErrNotInteger}, {Error: ErrNotNumber}},
+	}
+	if !reflect.DeepEqual(want.StringMap(), got.StringMap()) {
+		t.Errorf("Checker.Check:\nwant %v\ngot  %v", want, got)
+	}
+}
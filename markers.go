@@ -0,0 +1,38 @@
+package check
+
+import (
+	"mime/multipart"
+	"reflect"
+)
+
+// Optional marks a key as optional: when it has no value (or only empty values) and no file, the rules following the marker are skipped.
+// When a value is provided, all rules are applied as usual.
+//
+// For example, a blank phone field is accepted but a filled one must be a phone number:
+//
+//	"phone": {check.Optional, check.Phone},
+//
+// Markers are only effective in the rules of a Checker key or of the All, When and Each combinators.
+func Optional(errs Errors, form *multipart.Form, key string) {}
+
+// Nullable marks a key as nullable: when it's present with empty values only (like a JSON null), the rules following the marker are skipped.
+// Unlike Optional, the key must still be present: when it's missing, all rules are applied as usual (so a following Required fails).
+//
+// Markers are only effective in the rules of a Checker key or of the All, When and Each combinators.
+func Nullable(errs Errors, form *multipart.Form, key string) {}
+
+var (
+	optionalPointer = reflect.ValueOf(Optional).Pointer()
+	nullablePointer = reflect.ValueOf(Nullable).Pointer()
+)
+
+// marker tells if rule is a marker and, if so, if the following rules must be skipped for key.
+func marker(rule Rule, form *multipart.Form, key string) (skip, ok bool) {
+	switch reflect.ValueOf(rule).Pointer() {
+	case optionalPointer:
+		return !filled(form, key), true
+	case nullablePointer:
+		return formHas(form, key) && !filled(form, key), true
+	}
+	return false, false
+}
@@ -0,0 +1,38 @@
+package check
+
+import (
+	"mime/multipart"
+	"reflect"
+	"testing"
+)
+
+func TestMarkers(t *testing.T) {
+	var calls int
+	count := func(errs Errors, form *multipart.Form, key string) {
+		calls++
+	}
+	c := Checker{
+		"phone":    {Trim, Optional, count, Phone},
+		"missing":  {Optional, count, Required},
+		"note":     {Nullable, count, Required},
+		"absent":   {Nullable, Required},
+		"filled":   {Optional, Phone},
+		"eachNull": {Each(Nullable, Required)},
+	}
+	got := c.CheckValues(map[string][]string{
+		"phone":    {" "},
+		"note":     {""},
+		"filled":   {"foo"},
+		"eachNull": {"", "a"},
+	})
+	want := Errors{
+		"absent": {{Error: ErrRequired}},
+		"filled": {{Error: ErrNotPhone, Indexes: []int{0}}},
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("markers:\nwant %v\ngot  %v", want, got)
+	}
+	if calls != 0 {
+		t.Errorf("markers: want no rule applied after skipping marker, got %d calls", calls)
+	}
+}
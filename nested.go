@@ -0,0 +1,194 @@
+package check
+
+import (
+	"mime/multipart"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Nested rule checks the keys nested in key with checker c, as if they were a form on their own.
+// Nested keys are prefixed by the key and a dot or enclosed in brackets, like "address.city" or "address[city]" for the "city" key of c.
+// Errors are set for the full nested keys (like "address.city").
+//
+// So a checker can be reused for a part of different forms:
+//
+//	addressChecker := check.Checker{
+//		"city":    {check.Required},
+//		"country": {check.Required, check.MaxLen(2)},
+//	}
+//
+//	userChecker := check.Checker{
+//		"email":   {check.Required, check.Email},
+//		"address": {check.Nested(addressChecker)},
+//	}
+//
+// With the Strict option, the nested keys that are not in c have an ErrUnknownField error.
+func Nested(c Checker) Rule {
+	oc := c.Ordered()
+	return sanitizerOf(func(errs Errors, form *multipart.Form, key string) {
+		checkNested(oc, errs, form, key)
+	}, oc.rules())
+}
+
+// Dive rule checks each element of key with checker c, like Nested.
+// Elements are the nested keys with an index, like "items.0" (or "items[0]") for "items.0.sku" and "items.0.quantity".
+// Errors are set for the full nested keys (like "items.0.sku").
+//
+//	orderChecker := check.Checker{
+//		"items": {check.Dive(check.Checker{
+//			"sku":      {check.Required},
+//			"quantity": {check.Required, check.Integer, check.Min(1)},
+//		})},
+//	}
+func Dive(c Checker) Rule {
+	oc := c.Ordered()
+	return sanitizerOf(func(errs Errors, form *multipart.Form, key string) {
+		for _, elem := range nestedElements(form, key) {
+			checkNested(oc, errs, form, elem)
+		}
+	}, oc.rules())
+}
+
+// checkNested checks the keys nested in prefix with c.
+func checkNested(c OrderedChecker, errs Errors, form *multipart.Form, prefix string) {
+	if form == nil {
+		return
+	}
+	state := formState(form)
+	state.addNested(form, prefix)
+	sub := &multipart.Form{Value: make(map[string][]string), File: make(map[string][]*multipart.FileHeader)}
+	keys := make(map[string]string) // Sub-form key to form key.
+	for k, v := range form.Value {
+		if sk, ok := nestedKey(prefix, k); ok {
+			sub.Value[sk] = v
+			keys[sk] = k
+		}
+	}
+	for k, f := range form.File {
+		if sk, ok := nestedKey(prefix, k); ok {
+			sub.File[sk] = f
+			keys[sk] = k
+		}
+	}
+	formStates.Store(sub, state.derive(nestedPatterns(state.only, prefix)))
+	defer formStates.Delete(sub)
+	subErrs := make(Errors)
+	var allJobs []checkJob
+	for _, kr := range c {
+		for _, k := range formKeys(sub, kr.Key) {
+			allJobs = append(allJobs, checkJob{k, kr.Rules})
+		}
+	}
+	jobs := filterJobs(sub, allJobs, state.partial, formState(sub).only)
+	for _, job := range jobs {
+		if job.run(state.ctx, subErrs, sub) != nil {
+			break
+		}
+	}
+	if state.strict {
+		for _, k := range unknownKeys(sub, allJobs, nil, nil) {
+			if !matchAnyKey(state.allow, keys[k]) {
+				subErrs.Add(k, &Error{Error: ErrUnknownField})
+			}
+		}
+	}
+	for sk, skErrs := range subErrs {
+		k, ok := keys[sk]
+		if !ok {
+			k = prefix + "." + sk
+		}
+		for _, err := range skErrs {
+			errs.Add(k, err)
+		}
+	}
+}
+
+// nestedKey returns key without prefix, when key is nested in prefix.
+// So "address.city" and "address[city]" are both "city" for prefix "address".
+func nestedKey(prefix, key string) (string, bool) {
+	if !strings.HasPrefix(key, prefix) {
+		return "", false
+	}
+	rest := key[len(prefix):]
+	switch {
+	case len(rest) > 1 && rest[0] == '.':
+		return rest[1:], true
+	case len(rest) > 2 && rest[0] == '[':
+		i := strings.IndexByte(rest, ']')
+		if i < 2 {
+			return "", false
+		}
+		return rest[1:i] + rest[i+1:], true
+	}
+	return "", false
+}
+
+// nestedPatterns returns the patterns (see Only) nested in prefix, without prefix, to be used for the keys nested in prefix.
+// It's nil when a pattern selects prefix itself, as all its nested keys are then selected.
+// So "address.city" is "city" for prefix "address", and "items[*].sku" is "sku" for prefix "items.0".
+func nestedPatterns(patterns []string, prefix string) []string {
+	if patterns == nil {
+		return nil
+	}
+	path := splitKey(prefix)
+	nested := []string{}
+	for _, pattern := range patterns {
+		pp := splitKey(pattern)
+		if len(pp) <= len(path) && matchKey(pp, path[:len(pp)]) {
+			return nil
+		}
+		if len(pp) > len(path) && matchKey(pp[:len(path)], path) {
+			nested = append(nested, strings.Join(pp[len(path):], "."))
+		}
+	}
+	return nested
+}
+
+// nestedInAny tells if key is nested in one of the prefixes.
+func nestedInAny(prefixes []string, key string) bool {
+	for _, prefix := range prefixes {
+		if _, ok := nestedKey(prefix, key); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// nestedElements returns the prefixes of the indexed elements nested in key (like "items.0" or "items[0]" for "items"), in index order.
+func nestedElements(form *multipart.Form, key string) []string {
+	if form == nil {
+		return nil
+	}
+	indexes := make(map[string]int)
+	for _, k := range formAllKeys(form) {
+		sk, ok := nestedKey(key, k)
+		if !ok {
+			continue
+		}
+		seg := sk
+		if i := strings.IndexAny(seg, ".["); i != -1 {
+			seg = seg[:i]
+		}
+		n, err := strconv.Atoi(seg)
+		if err != nil || n < 0 {
+			continue
+		}
+		elem := key + "." + seg
+		if k[len(key)] == '[' {
+			elem = key + "[" + seg + "]"
+		}
+		indexes[elem] = n
+	}
+	elems := make([]string, 0, len(indexes))
+	for elem := range indexes {
+		elems = append(elems, elem)
+	}
+	sort.Slice(elems, func(i, j int) bool {
+		if indexes[elems[i]] != indexes[elems[j]] {
+			return indexes[elems[i]] < indexes[elems[j]]
+		}
+		return elems[i] < elems[j]
+	})
+	return elems
+}
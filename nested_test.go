@@ -0,0 +1,117 @@
+package check
+
+import (
+	"mime/multipart"
+	"reflect"
+	"testing"
+
+	"github.com/gowww/i18n"
+)
+
+var addressChecker = Checker{
+	"city":    {Required},
+	"country": {Required, MaxLen(2)},
+}
+
+func TestNested(t *testing.T) {
+	c := Checker{
+		"email":   {Required},
+		"address": {Nested(addressChecker)},
+		"billing": {Nested(Checker{"address": {Nested(addressChecker)}})},
+	}
+	got := c.CheckValues(map[string][]string{
+		"email":                   {"foo@example.com"},
+		"address[city]":           {"Paris"},
+		"address[country]":        {"France"},
+		"billing.address.country": {"FR"},
+	})
+	want := Errors{
+		"address[country]":     {{Error: ErrMaxLen, Args: []interface{}{i18n.TransInt(2)}, Indexes: []int{0}}},
+		"billing.address.city": {{Error: ErrRequired}},
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("Nested:\nwant %v\ngot  %v", want, got)
+	}
+}
+
+func TestDive(t *testing.T) {
+	c := Checker{
+		"items": {Dive(Checker{
+			"sku":      {Required},
+			"quantity": {Required, Integer},
+		})},
+	}
+	values := map[string][]string{
+		"items.0.sku":       {"A1"},
+		"items.0.quantity":  {"1"},
+		"items[1][sku]":     {"B2"},
+		"items[1].quantity": {"x"},
+		"items.10.quantity": {"2"},
+		"items.foo":         {"bar"},
+	}
+	got := c.CheckValues(values)
+	want := Errors{
+		"items[1].quantity": {{Error: ErrNotInteger, Indexes: []int{0}}},
+		"items.10.sku":      {{Error: ErrRequired}},
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("Dive:\nwant %v\ngot  %v", want, got)
+	}
+	got = c.CheckValues(values, Strict())
+	want["items.foo"] = []*Error{{Error: ErrUnknownField}}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("Dive with Strict:\nwant %v\ngot  %v", want, got)
+	}
+}
+
+func TestNestedStrict(t *testing.T) {
+	c := Checker{"address": {Nested(addressChecker)}}
+	form := &multipart.Form{Value: map[string][]string{
+		"address.city":    {"Paris"},
+		"address.country": {"FR"},
+		"address.zip":     {"75001"},
+		"address.note":    {""},
+	}}
+	got := c.Check(form, Strict("address.note"))
+	want := Errors{"address.zip": {{Error: ErrUnknownField}}}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("Nested with Strict:\nwant %v\ngot  %v", want, got)
+	}
+}
+
+func TestNestedPartialOnly(t *testing.T) {
+	c := Checker{
+		"email":   {Required},
+		"address": {Nested(addressChecker)},
+		"billing": {Nested(Checker{"address": {Nested(addressChecker)}})},
+	}
+	got := c.CheckPartial(&multipart.Form{Value: map[string][]string{"address.city": {""}}})
+	want := Errors{"address.city": {{Error: ErrRequired}}}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("Nested with CheckPartial:\nwant %v\ngot  %v", want, got)
+	}
+	got = c.CheckOnly(&multipart.Form{Value: map[string][]string{}}, "address.city", "billing.address.country")
+	want = Errors{
+		"address.city":            {{Error: ErrRequired}},
+		"billing.address.country": {{Error: ErrRequired}},
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("Nested with CheckOnly:\nwant %v\ngot  %v", want, got)
+	}
+	c = Checker{"items": {Dive(Checker{"sku": {Required}, "quantity": {Required}})}}
+	got = c.CheckOnly(&multipart.Form{Value: map[string][]string{"items.0.quantity": {"1"}, "items.1.sku": {"B2"}}}, "items[*].sku")
+	want = Errors{"items.0.sku": {{Error: ErrRequired}}}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("Dive with CheckOnly:\nwant %v\ngot  %v", want, got)
+	}
+	c = Checker{"email": {Required}, "items": {Dive(Checker{"sku": {Required}})}, "user.address": {Nested(addressChecker)}}
+	got = c.CheckValues(map[string][]string{"items.0.sku": {""}, "user.address.city": {"Paris"}}, Only("email", "items", "user.address"))
+	want = Errors{
+		"email":                {{Error: ErrRequired}},
+		"items.0.sku":          {{Error: ErrRequired}},
+		"user.address.country": {{Error: ErrRequired}},
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("Dive and dotted Nested with shorter Only keys:\nwant %v\ngot  %v", want, got)
+	}
+}
@@ -0,0 +1,164 @@
+package check
+
+import (
+	"mime/multipart"
+	"time"
+)
+
+// An Option changes the way a check is made.
+type Option func(*options)
+
+type options struct {
+	workers   int
+	strict    bool
+	allow     []string
+	partial   bool
+	only      []string
+	scenarios []string
+
+	maxMemory   int64
+	maxBodySize int64
+	stream      bool
+
+	responder Responder
+	clock     func() time.Time
+}
+
+func newOptions(opts []Option) *options {
+	o := new(options)
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// filterJobs returns the jobs for the keys selected by the Partial and Only options.
+func (o *options) filterJobs(form *multipart.Form, jobs []checkJob) []checkJob {
+	return filterJobs(form, jobs, o.partial, o.only)
+}
+
+// filterJobs returns the jobs for the keys selected by partial (see Partial) and only (see Only).
+// A job whose key is a parent of an only key (like "address" for "address.city") is kept, so its Nested or Dive rule checks the selected nested keys.
+func filterJobs(form *multipart.Form, jobs []checkJob, partial bool, only []string) []checkJob {
+	if !partial && only == nil {
+		return jobs
+	}
+	var filtered []checkJob
+	for _, job := range jobs {
+		if partial && !formPresent(form, job.key) {
+			continue
+		}
+		if only != nil && !matchAnyKeyPath(only, job.key) && !matchAnyParentKey(only, job.key) {
+			continue
+		}
+		filtered = append(filtered, job)
+	}
+	return filtered
+}
+
+// Parallel makes the check apply the rules of different keys concurrently, with n goroutines at most.
+// It's useful when there are slow rules (like Unique or file rules) for multiple keys.
+//
+// Rules of a same key are still applied in order, so a rule can rely on the errors of the previous ones (like Unique does).
+// But each key is checked with its own errors map (merged into the result at the end), so a rule cannot rely on the errors of another key.
+//
+// The result is the same as for a sequential check.
+// For this, the rules of a key with sanitizers (like Trim, even inside Each or Nested) are applied alone, after the keys before it and before the keys after it.
+// A custom rule changing values must not be used with Parallel.
+func Parallel(n int) Option {
+	return func(o *options) {
+		o.workers = n
+	}
+}
+
+// Strict makes the check set an ErrUnknownField error for each form value or file key that is not in the checker.
+// It prevents mass assignment, when a client sends unexpected fields (like "is_admin") to be saved with the expected ones.
+//
+// Keys in allow (which can have wildcards like checker keys) are accepted even without rules, like a CSRF token:
+//
+//	errs := userChecker.CheckRequest(r, check.Strict("csrf_token"))
+func Strict(allow ...string) Option {
+	return func(o *options) {
+		o.strict = true
+		o.allow = append(o.allow, allow...)
+	}
+}
+
+// Partial makes the check apply the rules of the keys present in the form only (values or files, nested ones included).
+// It's useful for a partial update (like a PATCH request), where untouched fields must not fail Required.
+//
+// See Checker.CheckPartial.
+func Partial() Option {
+	return func(o *options) {
+		o.partial = true
+	}
+}
+
+// Only makes the check apply the rules of keys only, ignoring the others.
+// Keys can have wildcards like checker keys, and a key also selects its nested keys (so "address" selects "address.city").
+// A key also selects its parent keys, so Only("address.city") applies the rules of "address" and a Nested rule there only checks "city".
+// It's useful for a multi-step form, where each step only contains a part of the fields.
+//
+// With Strict, fields of the other keys are still known and never set as unknown.
+//
+// See Checker.CheckOnly.
+func Only(keys ...string) Option {
+	return func(o *options) {
+		o.only = append(o.only, keys...)
+		if o.only == nil {
+			o.only = []string{}
+		}
+	}
+}
+
+// Scenario sets the scenarios of the check (like "create" or "update"), used by the On and Except rules.
+// So a single checker can be used for multiple actions:
+//
+//	userChecker := check.Checker{
+//		"email":    {check.Required, check.Email},
+//		"password": {check.On("create", check.Required), check.Optional, check.MinLen(8)},
+//	}
+//
+//	errs := userChecker.CheckRequest(r, check.Scenario("update"))
+func Scenario(names ...string) Option {
+	return func(o *options) {
+		o.scenarios = append(o.scenarios, names...)
+	}
+}
+
+// MaxMemory sets the maximum bytes of a multipart request body stored in memory (32 MB by default), the remaining files being stored on disk.
+// See http.Request.ParseMultipartForm.
+// A JSON request body is read in memory, so it cannot be larger: the check then fails as with MaxBodySize.
+func MaxMemory(n int64) Option {
+	return func(o *options) {
+		o.maxMemory = n
+	}
+}
+
+// MaxBodySize limits the bytes read from a request body to n.
+// When the body is larger, Checker.CheckRequestContext returns an error wrapping ErrRequestTooLarge, and Checker.CheckRequest an ErrMaxBodySize error.
+func MaxBodySize(n int64) Option {
+	return func(o *options) {
+		o.maxBodySize = n
+	}
+}
+
+// Stream makes the check of a multipart request read the body part by part, applying the rules of a file key as each file arrives (after its first 512 bytes and then for each chunk).
+// Reading stops on the first error that cannot disappear with the rest of the file (from rules like MaxFileSize, MaxTotalFileSize, MaxCount, FileType or Image), and the check only returns this error.
+// So an oversized or wrong-type upload is rejected before being fully received.
+//
+// Otherwise, the request form is parsed as usual (see MaxMemory) and checked.
+// As the rules of a file key are applied many times, they should be fast (no database query, for example).
+func Stream() Option {
+	return func(o *options) {
+		o.stream = true
+	}
+}
+
+// Clock sets the function returning the current time of the check, used by the date rules with relative bounds (like Today) and by the age rules.
+// It's time.Now by default, and a fixed time makes a check deterministic (in tests, for example).
+func Clock(now func() time.Time) Option {
+	return func(o *options) {
+		o.clock = now
+	}
+}
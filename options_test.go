@@ -0,0 +1,185 @@
+package check
+
+import (
+	"mime/multipart"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestParallel(t *testing.T) {
+	var running, maxRunning int32
+	slow := func(errs Errors, form *multipart.Form, key string) {
+		n := atomic.AddInt32(&running, 1)
+		for {
+			m := atomic.LoadInt32(&maxRunning)
+			if n <= m || atomic.CompareAndSwapInt32(&maxRunning, m, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		atomic.AddInt32(&running, -1)
+	}
+	c := Checker{
+		"email":             {slow, Required, Email, slow},
+		"phone":             {slow, Phone},
+		"stars":             {slow, Required, Range(3, 5)},
+		"items[*].quantity": {slow, Integer},
+	}
+	values := map[string][]string{
+		"email":             {"foo"},
+		"stars":             {"2"},
+		"items[0].quantity": {"a"},
+		"items[1].quantity": {"1"},
+	}
+	want := c.CheckValues(values)
+	got := c.CheckValues(values, Parallel(2))
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("Parallel:\nwant %v\ngot  %v", want, got)
+	}
+	if atomic.LoadInt32(&maxRunning) != 2 {
+		t.Errorf("Parallel: want 2 concurrent rules at most, got %d", maxRunning)
+	}
+}
+
+func TestParallelSanitizers(t *testing.T) {
+	c := Checker{
+		"a": {Trim, Required},
+		"b": {Same("a")},
+		"c": {Trim},
+		"d": {MinLen(3), Trim},
+		"e": {Same("d")},
+	}
+	values := map[string][]string{"a": {" foo "}, "b": {"foo"}, "c": {" bar "}, "d": {" x "}, "e": {"x"}}
+	want := c.CheckValues(values)
+	if !reflect.DeepEqual(want, Errors{}) {
+		t.Fatalf("sequential check: want no errors, got %v", want)
+	}
+	for i := 0; i < 10; i++ {
+		if got := c.CheckValues(values, Parallel(4)); !reflect.DeepEqual(want, got) {
+			t.Fatalf("Parallel:\nwant %v\ngot  %v", want, got)
+		}
+	}
+}
+
+func TestParallelWrappedSanitizers(t *testing.T) {
+	c := Checker{
+		"address": {Nested(Checker{"city": {Trim, Required}})},
+		"tags":    {Each(Trim)},
+		"x":       {Same("address.city")},
+		"y":       {Same("tags")},
+	}
+	values := map[string][]string{"address.city": {" Paris "}, "tags": {" a ", "b "}, "x": {"Paris"}, "y": {"a", "b"}}
+	want := c.CheckValues(values)
+	if !reflect.DeepEqual(want, Errors{}) {
+		t.Fatalf("sequential check: want no errors, got %v", want)
+	}
+	for i := 0; i < 10; i++ {
+		if got := c.CheckValues(values, Parallel(4)); !reflect.DeepEqual(want, got) {
+			t.Fatalf("Parallel:\nwant %v\ngot  %v", want, got)
+		}
+	}
+}
+
+func TestStrict(t *testing.T) {
+	c := Checker{
+		"email":             {Required},
+		"items[*].quantity": {Integer},
+	}
+	form := &multipart.Form{
+		Value: map[string][]string{
+			"email":             {"foo@example.com"},
+			"items[0].quantity": {"1"},
+			"items[0].price":    {"1"},
+			"is_admin":          {"1"},
+			"csrf_token":        {"token"},
+			"meta.0":            {"a"},
+		},
+		File: map[string][]*multipart.FileHeader{"picture": nil},
+	}
+	want := Errors{
+		"items[0].price": {{Error: ErrUnknownField}},
+		"is_admin":       {{Error: ErrUnknownField}},
+		"picture":        {{Error: ErrUnknownField}},
+	}
+	got := c.Check(form, Strict("csrf_token", "meta[*]"))
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("Strict:\nwant %v\ngot  %v", want, got)
+	}
+	if got = c.Check(form); got.NotEmpty() {
+		t.Errorf("no Strict: want no errors, got %v", got)
+	}
+}
+
+func TestPartial(t *testing.T) {
+	c := Checker{
+		"email":             {Required, Email},
+		"name":              {Required},
+		"address":           {Required},
+		"items[*].quantity": {Required, Integer},
+	}
+	got := c.CheckPartial(&multipart.Form{Value: map[string][]string{
+		"email":        {"foo"},
+		"address.city": {"Paris"},
+	}})
+	want := Errors{
+		"email":   {{Error: ErrNotEmail, Indexes: []int{0}}},
+		"address": {{Error: ErrRequired}},
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("CheckPartial:\nwant %v\ngot  %v", want, got)
+	}
+}
+
+func TestOnly(t *testing.T) {
+	c := Checker{
+		"email":             {Required},
+		"name":              {Required},
+		"address.city":      {Required},
+		"items[*].quantity": {Required},
+	}
+	form := &multipart.Form{Value: map[string][]string{
+		"items[0].quantity": {""},
+		"is_admin":          {"1"},
+	}}
+	got := c.CheckOnly(form, "email", "address", "items[*]")
+	want := Errors{
+		"email":             {{Error: ErrRequired}},
+		"address.city":      {{Error: ErrRequired}},
+		"items[0].quantity": {{Error: ErrRequired}},
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("CheckOnly:\nwant %v\ngot  %v", want, got)
+	}
+	got = c.Check(form, Only("items[*].quantity"), Strict())
+	want = Errors{
+		"items[0].quantity": {{Error: ErrRequired}},
+		"is_admin":          {{Error: ErrUnknownField}},
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("Only with Strict:\nwant %v\ngot  %v", want, got)
+	}
+}
+
+func TestScenario(t *testing.T) {
+	c := Checker{
+		"email":    {On("create", Required), Optional, Email},
+		"password": {Except("update", Required), MinLen(8)},
+	}
+	cases := []struct {
+		scenarios []string
+		want      Errors
+	}{
+		{nil, Errors{"password": {{Error: ErrRequired}}}},
+		{[]string{"create"}, Errors{"email": {{Error: ErrRequired}}, "password": {{Error: ErrRequired}}}},
+		{[]string{"update"}, Errors{}},
+		{[]string{"create", "update"}, Errors{"email": {{Error: ErrRequired}}}},
+	}
+	for _, c2 := range cases {
+		got := c.CheckValues(nil, Scenario(c2.scenarios...))
+		if !reflect.DeepEqual(c2.want, got) {
+			t.Errorf("Scenario(%v):\nwant %v\ngot  %v", c2.scenarios, c2.want, got)
+		}
+	}
+}
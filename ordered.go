@@ -0,0 +1,164 @@
+package check
+
+import (
+	"context"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"sort"
+)
+
+// KeyRules contains a key with its checking rules.
+type KeyRules struct {
+	Key   string
+	Rules []Rule
+}
+
+// Key returns a KeyRules for key, to be used inside an OrderedChecker.
+func Key(key string, rules ...Rule) KeyRules {
+	return KeyRules{Key: key, Rules: rules}
+}
+
+// An OrderedChecker contains keys with their checking rules, like a Checker, but keys are checked in declaration order:
+//
+//	userChecker := check.OrderedChecker{
+//		check.Key("email", check.Required, check.Email),
+//		check.Key("emailConfirm", check.Required, check.Same("email")),
+//	}
+//
+// So a rule can rely on the errors of a previous key.
+// When a key matches multiple form keys (see Checker), they are checked in alphabetical order.
+type OrderedChecker []KeyRules
+
+// Ordered returns c as an OrderedChecker, with keys in alphabetical order.
+func (c Checker) Ordered() OrderedChecker {
+	oc := make(OrderedChecker, 0, len(c))
+	for _, key := range c.Keys() {
+		oc = append(oc, KeyRules{Key: key, Rules: c[key]})
+	}
+	return oc
+}
+
+// Keys returns the checker keys in checking order (alphabetical).
+func (c Checker) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Keys returns the checker keys in checking order (declaration).
+func (c OrderedChecker) Keys() []string {
+	keys := make([]string, len(c))
+	for i, kr := range c {
+		keys[i] = kr.Key
+	}
+	return keys
+}
+
+// Check works like Checker.Check.
+func (c OrderedChecker) Check(form *multipart.Form, opts ...Option) Errors {
+	_, errs, _ := c.check(context.Background(), form, opts)
+	return errs
+}
+
+// CheckE works like Checker.CheckE.
+func (c OrderedChecker) CheckE(form *multipart.Form, opts ...Option) (Errors, error) {
+	return c.CheckContext(context.Background(), form, opts...)
+}
+
+// CheckContext works like Checker.CheckContext.
+func (c OrderedChecker) CheckContext(ctx context.Context, form *multipart.Form, opts ...Option) (Errors, error) {
+	_, errs, err := c.check(ctx, form, opts)
+	if err != nil {
+		return errs, err
+	}
+	return errs, errs.failure()
+}
+
+// CheckPartial works like Checker.CheckPartial.
+func (c OrderedChecker) CheckPartial(form *multipart.Form, opts ...Option) Errors {
+	return c.Check(form, append([]Option{Partial()}, opts...)...)
+}
+
+// CheckOnly works like Checker.CheckOnly.
+func (c OrderedChecker) CheckOnly(form *multipart.Form, keys ...string) Errors {
+	return c.Check(form, Only(keys...))
+}
+
+// CheckClean works like Checker.CheckClean.
+func (c OrderedChecker) CheckClean(form *multipart.Form, opts ...Option) (*multipart.Form, Errors) {
+	form, errs, _ := c.check(context.Background(), form, opts)
+	return form, errs
+}
+
+// CheckValues works like Checker.CheckValues.
+func (c OrderedChecker) CheckValues(values map[string][]string, opts ...Option) Errors {
+	return c.Check(&multipart.Form{Value: values}, opts...)
+}
+
+// CheckFiles works like Checker.CheckFiles.
+func (c OrderedChecker) CheckFiles(files map[string][]*multipart.FileHeader, opts ...Option) Errors {
+	return c.Check(&multipart.Form{File: files}, opts...)
+}
+
+// CheckRequest works like Checker.CheckRequest.
+func (c OrderedChecker) CheckRequest(r *http.Request, opts ...Option) Errors {
+	form, errs, err := requestForm(r, c, opts)
+	if err != nil {
+		return requestErrors(err)
+	}
+	if errs != nil {
+		return errs
+	}
+	return c.Check(form, opts...)
+}
+
+// CheckRequestContext works like Checker.CheckRequestContext.
+func (c OrderedChecker) CheckRequestContext(r *http.Request, opts ...Option) (Errors, error) {
+	form, errs, err := requestForm(r, c, opts)
+	if err != nil {
+		return make(Errors), err
+	}
+	if errs != nil {
+		return errs, nil
+	}
+	return c.CheckContext(r.Context(), form, opts...)
+}
+
+// CheckRequestClean works like Checker.CheckRequestClean.
+func (c OrderedChecker) CheckRequestClean(r *http.Request, opts ...Option) (*multipart.Form, Errors) {
+	form, errs, err := requestForm(r, c, opts)
+	if err != nil {
+		return form, requestErrors(err)
+	}
+	if errs != nil {
+		return form, errs
+	}
+	return c.CheckClean(form, opts...)
+}
+
+// CheckJSON works like Checker.CheckJSON.
+func (c OrderedChecker) CheckJSON(r io.Reader, opts ...Option) (Errors, error) {
+	values, err := jsonValues(r)
+	if err != nil {
+		return make(Errors), err
+	}
+	return c.CheckValues(values, opts...), nil
+}
+
+// CheckStruct works like Checker.CheckStruct.
+func (c OrderedChecker) CheckStruct(v interface{}, opts ...Option) Errors {
+	return c.Check(structForm(v), opts...)
+}
+
+// rules returns the rules of all keys of c.
+func (c OrderedChecker) rules() []Rule {
+	var rules []Rule
+	for _, kr := range c {
+		rules = append(rules, kr.Rules...)
+	}
+	return rules
+}
@@ -0,0 +1,46 @@
+package check
+
+import (
+	"mime/multipart"
+	"reflect"
+	"testing"
+)
+
+func TestOrderedChecker(t *testing.T) {
+	var order []string
+	trace := func(errs Errors, form *multipart.Form, key string) {
+		order = append(order, key)
+	}
+	emailOK := func(errs Errors, form *multipart.Form, key string) {
+		if errs.Has("email") {
+			errs.Add(key, &Error{Error: ErrIllogical})
+		}
+	}
+	c := OrderedChecker{
+		Key("email", trace, Required, Email),
+		Key("emailConfirm", trace, emailOK),
+		Key("b[*]", trace),
+		Key("a", trace),
+	}
+	errs := c.CheckValues(map[string][]string{"email": {"foo"}, "b[1]": {""}, "b[0]": {""}})
+	want := Errors{
+		"email":        {{Error: ErrNotEmail, Indexes: []int{0}}},
+		"emailConfirm": {{Error: ErrIllogical}},
+	}
+	if !reflect.DeepEqual(want, errs) {
+		t.Errorf("OrderedChecker.Check:\nwant %v\ngot  %v", want, errs)
+	}
+	if wantOrder := []string{"email", "emailConfirm", "b[0]", "b[1]", "a"}; !reflect.DeepEqual(wantOrder, order) {
+		t.Errorf("OrderedChecker.Check order: want %v, got %v", wantOrder, order)
+	}
+	if wantKeys := []string{"email", "emailConfirm", "b[*]", "a"}; !reflect.DeepEqual(wantKeys, c.Keys()) {
+		t.Errorf("OrderedChecker.Keys: want %v, got %v", wantKeys, c.Keys())
+	}
+}
+
+func TestCheckerKeys(t *testing.T) {
+	c := Checker{"c": nil, "a": nil, "b": nil}
+	if want := []string{"a", "b", "c"}; !reflect.DeepEqual(want, c.Keys()) {
+		t.Errorf("Checker.Keys: want %v, got %v", want, c.Keys())
+	}
+}
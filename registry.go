@@ -0,0 +1,385 @@
+package check
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+// A RuleFactory makes a Rule from string arguments.
+type RuleFactory func(args ...string) (Rule, error)
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]RuleFactory{
+		"after":               boundArg(After),
+		"afterfield":          keyValuesArgs(AfterField),
+		"alpha":               noArgs(Alpha),
+		"alphanumeric":        noArgs(Alphanumeric),
+		"alphanumericunicode": scriptArgs(AlphanumericUnicode),
+		"alphaunicode":        scriptArgs(AlphaUnicode),
+		"before":              boundArg(Before),
+		"beforefield":         keyValuesArgs(BeforeField),
+		"collapsespace":       noArgs(CollapseSpace),
+		"date":                layoutArg(Date),
+		"daterange":           boundArgs2(DateRange),
+		"datetime":            layoutArg(DateTime),
+		"distinct":            noArgs(Distinct),
+		"email":               noArgs(Email),
+		"filetype":            func(args ...string) (Rule, error) { return FileType(args...), nil },
+		"image":               noArgs(Image),
+		"in":                  valuesArgs(In),
+		"infold":              valuesArgs(InFold),
+		"integer":             noArgs(Integer),
+		"latitude":            noArgs(Latitude),
+		"longitude":           noArgs(Longitude),
+		"lower":               noArgs(Lower),
+		"max":                 floatArg(Max),
+		"maxage":              intLayoutsArg(MaxAge),
+		"maxcount":            intArg(MaxCount),
+		"maxfilesize":         int64Arg(MaxFileSize),
+		"maxgraphemelen":      intArg(MaxGraphemeLen),
+		"maxlen":              intArg(MaxLen),
+		"maxrunelen":          intArg(MaxRuneLen),
+		"maxtotalfilesize":    int64Arg(MaxTotalFileSize),
+		"min":                 floatArg(Min),
+		"minage":              intLayoutsArg(MinAge),
+		"mincount":            intArg(MinCount),
+		"minfilesize":         int64Arg(MinFileSize),
+		"mingraphemelen":      intArg(MinGraphemeLen),
+		"minlen":              intArg(MinLen),
+		"minrunelen":          intArg(MinRuneLen),
+		"nfc":                 noArgs(NFC),
+		"normalizeemail":      noArgs(NormalizeEmail),
+		"notin":               valuesArgs(NotIn),
+		"notinfold":           valuesArgs(NotInFold),
+		"nullable":            noArgs(Nullable),
+		"number":              noArgs(Number),
+		"optional":            noArgs(Optional),
+		"phone":               noArgs(Phone),
+		"phonedigits":         noArgs(PhoneDigits),
+		"range":               floatArgs2(Range),
+		"rangecount":          intArgs2(RangeCount),
+		"rangefilesize":       int64Args2(RangeFileSize),
+		"rangegraphemelen":    intArgs2(RangeGraphemeLen),
+		"rangelen":            intArgs2(RangeLen),
+		"rangerunelen":        intArgs2(RangeRuneLen),
+		"required":            noArgs(Required),
+		"requiredif":          keyValuesArgs(RequiredIf),
+		"requiredunless":      keyValuesArgs(RequiredUnless),
+		"requiredwith":        func(args ...string) (Rule, error) { return RequiredWith(args...), nil },
+		"requiredwithout":     func(args ...string) (Rule, error) { return RequiredWithout(args...), nil },
+		"same":                func(args ...string) (Rule, error) { return Same(args...), nil },
+		"stripcontrol":        noArgs(StripControl),
+		"trim":                noArgs(Trim),
+		"url":                 noArgs(URL),
+	}
+)
+
+// Register makes a rule available by name for ParseRules and struct tags.
+// Names are case-insensitive and registering an existing name replaces its factory.
+//
+// All the rules and sanitizers of this package are registered under their lower case name (like "maxlen"), except Unique and InDB which need a database, and InFunc and NotInFunc which need a function.
+//
+// It panics if name is empty or f is nil.
+func Register(name string, f RuleFactory) {
+	if name == "" || f == nil {
+		panic("check: Register needs a name and a factory")
+	}
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry[strings.ToLower(name)] = f
+}
+
+// NewRule returns the registered rule name, made with args.
+func NewRule(name string, args ...string) (Rule, error) {
+	registryMu.RLock()
+	f, ok := registry[strings.ToLower(name)]
+	registryMu.RUnlock()
+	if !ok {
+		return nil, &ParseError{Rule: name, Err: errUnknownRule}
+	}
+	rule, err := f(args...)
+	if err != nil {
+		return nil, &ParseError{Rule: name, Err: err}
+	}
+	return rule, nil
+}
+
+var errUnknownRule = errors.New("unknown rule")
+
+// A ParseError is returned when a rule cannot be made from its name and arguments.
+type ParseError struct {
+	Rule string
+	Err  error
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("check: rule %q: %v", e.Rule, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// ParseRules returns the rules of an expression like "required|email|maxlen:255|range:1,10".
+// Rules are separated by pipes, and a rule name (see Register) is followed by its comma separated arguments after a colon.
+// An argument cannot contain a comma or a pipe, so a time layout argument (like for "date" or "after") cannot have commas.
+// It's also the syntax of the struct tags of FromStruct.
+func ParseRules(expr string) ([]Rule, error) {
+	var rules []Rule
+	for _, s := range strings.Split(expr, "|") {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			return nil, &ParseError{Rule: s, Err: errors.New("empty rule")}
+		}
+		var args []string
+		if i := strings.IndexByte(s, ':'); i != -1 {
+			args = strings.Split(s[i+1:], ",")
+			for j := range args {
+				args[j] = strings.TrimSpace(args[j])
+			}
+			s = strings.TrimSpace(s[:i])
+		}
+		rule, err := NewRule(s, args...)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// MustParseRules is like ParseRules but panics if the expression cannot be parsed.
+func MustParseRules(expr string) []Rule {
+	rules, err := ParseRules(expr)
+	if err != nil {
+		panic(err)
+	}
+	return rules
+}
+
+// ParseChecker returns a Checker from a map of keys and their rules expressions (see ParseRules).
+func ParseChecker(exprs map[string]string) (Checker, error) {
+	c := make(Checker, len(exprs))
+	for key, expr := range exprs {
+		rules, err := ParseRules(expr)
+		if err != nil {
+			return nil, fmt.Errorf("%w (for key %q)", err, key)
+		}
+		c[key] = rules
+	}
+	return c, nil
+}
+
+func noArgs(r Rule) RuleFactory {
+	return func(args ...string) (Rule, error) {
+		if len(args) != 0 {
+			return nil, fmt.Errorf("want no arguments, got %d", len(args))
+		}
+		return r, nil
+	}
+}
+
+// scriptArgs makes a RuleFactory for a rule receiving Unicode scripts, from their names (like "Latin", see unicode.Scripts).
+func scriptArgs(f func(...*unicode.RangeTable) Rule) RuleFactory {
+	return func(args ...string) (Rule, error) {
+		scripts := make([]*unicode.RangeTable, len(args))
+		for i, arg := range args {
+			script, ok := unicode.Scripts[arg]
+			if !ok {
+				return nil, fmt.Errorf("unknown script %q", arg)
+			}
+			scripts[i] = script
+		}
+		return f(scripts...), nil
+	}
+}
+
+func valuesArgs(f func(...string) Rule) RuleFactory {
+	return func(args ...string) (Rule, error) {
+		if len(args) == 0 {
+			return nil, errors.New("want at least 1 value argument")
+		}
+		return f(args...), nil
+	}
+}
+
+func keyValuesArgs(f func(string, ...string) Rule) RuleFactory {
+	return func(args ...string) (Rule, error) {
+		if len(args) == 0 {
+			return nil, errors.New("want a key argument")
+		}
+		return f(args[0], args[1:]...), nil
+	}
+}
+
+// layoutArg makes a RuleFactory for a rule receiving an optional time layout.
+func layoutArg(f func(string) Rule) RuleFactory {
+	return func(args ...string) (Rule, error) {
+		if len(args) > 1 {
+			return nil, fmt.Errorf("want at most 1 argument, got %d", len(args))
+		}
+		var layout string
+		if len(args) == 1 {
+			layout = args[0]
+		}
+		return f(layout), nil
+	}
+}
+
+// boundArg makes a RuleFactory for a rule receiving a time bound, followed by optional time layouts.
+func boundArg(f func(TimeBound, ...string) Rule) RuleFactory {
+	return func(args ...string) (Rule, error) {
+		b, layouts, err := parseBounds(args, 1)
+		if err != nil {
+			return nil, err
+		}
+		return f(b[0], layouts...), nil
+	}
+}
+
+// boundArgs2 makes a RuleFactory for a rule receiving 2 time bounds, followed by optional time layouts.
+func boundArgs2(f func(TimeBound, TimeBound, ...string) Rule) RuleFactory {
+	return func(args ...string) (Rule, error) {
+		b, layouts, err := parseBounds(args, 2)
+		if err != nil {
+			return nil, err
+		}
+		return f(b[0], b[1], layouts...), nil
+	}
+}
+
+// parseBounds parses want time bounds from args, and returns the remaining args as time layouts.
+// A bound is "now", "today", a Go duration relative to now (like "-24h") or a date (parsed with the layouts or TimeLayouts).
+func parseBounds(args []string, want int) (bounds []TimeBound, layouts []string, err error) {
+	if len(args) < want {
+		return nil, nil, fmt.Errorf("want at least %d arguments, got %d", want, len(args))
+	}
+	layouts = args[want:]
+	bounds = make([]TimeBound, want)
+	for i, arg := range args[:want] {
+		switch arg {
+		case "now":
+			bounds[i] = Now()
+		case "today":
+			bounds[i] = Today()
+		default:
+			if d, err := time.ParseDuration(arg); err == nil {
+				bounds[i] = FromNow(d)
+			} else if t, ok := parseTime(arg, layouts); ok {
+				bounds[i] = At(t)
+			} else {
+				return nil, nil, fmt.Errorf("argument %q is not a time bound", arg)
+			}
+		}
+	}
+	return bounds, layouts, nil
+}
+
+// intLayoutsArg makes a RuleFactory for a rule receiving an integer, followed by optional time layouts.
+func intLayoutsArg(f func(int, ...string) Rule) RuleFactory {
+	return func(args ...string) (Rule, error) {
+		if len(args) == 0 {
+			return nil, errors.New("want at least 1 argument")
+		}
+		n, err := parseInts(args[:1], 1)
+		if err != nil {
+			return nil, err
+		}
+		return f(int(n[0]), args[1:]...), nil
+	}
+}
+
+func intArg(f func(int) Rule) RuleFactory {
+	return func(args ...string) (Rule, error) {
+		n, err := parseInts(args, 1)
+		if err != nil {
+			return nil, err
+		}
+		return f(int(n[0])), nil
+	}
+}
+
+func intArgs2(f func(int, int) Rule) RuleFactory {
+	return func(args ...string) (Rule, error) {
+		n, err := parseInts(args, 2)
+		if err != nil {
+			return nil, err
+		}
+		return f(int(n[0]), int(n[1])), nil
+	}
+}
+
+func int64Arg(f func(int64) Rule) RuleFactory {
+	return func(args ...string) (Rule, error) {
+		n, err := parseInts(args, 1)
+		if err != nil {
+			return nil, err
+		}
+		return f(n[0]), nil
+	}
+}
+
+func int64Args2(f func(int64, int64) Rule) RuleFactory {
+	return func(args ...string) (Rule, error) {
+		n, err := parseInts(args, 2)
+		if err != nil {
+			return nil, err
+		}
+		return f(n[0], n[1]), nil
+	}
+}
+
+func floatArg(f func(float64) Rule) RuleFactory {
+	return func(args ...string) (Rule, error) {
+		n, err := parseFloats(args, 1)
+		if err != nil {
+			return nil, err
+		}
+		return f(n[0]), nil
+	}
+}
+
+func floatArgs2(f func(float64, float64) Rule) RuleFactory {
+	return func(args ...string) (Rule, error) {
+		n, err := parseFloats(args, 2)
+		if err != nil {
+			return nil, err
+		}
+		return f(n[0], n[1]), nil
+	}
+}
+
+func parseInts(args []string, want int) ([]int64, error) {
+	if len(args) != want {
+		return nil, fmt.Errorf("want %d arguments, got %d", want, len(args))
+	}
+	n := make([]int64, want)
+	for i, arg := range args {
+		var err error
+		if n[i], err = strconv.ParseInt(arg, 10, 64); err != nil {
+			return nil, fmt.Errorf("argument %q is not an integer", arg)
+		}
+	}
+	return n, nil
+}
+
+func parseFloats(args []string, want int) ([]float64, error) {
+	if len(args) != want {
+		return nil, fmt.Errorf("want %d arguments, got %d", want, len(args))
+	}
+	n := make([]float64, want)
+	for i, arg := range args {
+		var err error
+		if n[i], err = strconv.ParseFloat(arg, 64); err != nil {
+			return nil, fmt.Errorf("argument %q is not a number", arg)
+		}
+	}
+	return n, nil
+}
@@ -0,0 +1,71 @@
+package check
+
+import (
+	"errors"
+	"mime/multipart"
+	"reflect"
+	"testing"
+)
+
+func TestParseRules(t *testing.T) {
+	rules, err := ParseRules("required | email|maxlen:10")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := Checker{"email": rules}.CheckValues(map[string][]string{"email": {"foobar@example.com"}})
+	want := Errors{"email": {{Error: ErrMaxLen, Args: []interface{}{"10"}}}}
+	if !reflect.DeepEqual(want.StringMap(), got.StringMap()) {
+		t.Errorf("ParseRules: want %v, got %v", want, got)
+	}
+
+	for _, expr := range []string{
+		"",
+		"required||email",
+		"unknown",
+		"range:1",
+		"range:1,a",
+		"maxlen",
+		"email:foo",
+		"before:soon",
+		"daterange:today",
+		"date:2006,01",
+	} {
+		if _, err := ParseRules(expr); err == nil {
+			t.Errorf("ParseRules(%q): want error", expr)
+		} else if _, ok := err.(*ParseError); !ok {
+			t.Errorf("ParseRules(%q): want *ParseError, got %T", expr, err)
+		}
+	}
+}
+
+func TestParseChecker(t *testing.T) {
+	_, err := ParseChecker(map[string]string{"email": "required", "stars": "range:1"})
+	var perr *ParseError
+	if !errors.As(err, &perr) || perr.Rule != "range" {
+		t.Errorf("ParseChecker: want *ParseError for rule %q, got %v", "range", err)
+	}
+}
+
+func TestRegister(t *testing.T) {
+	Register("Foo", func(args ...string) (Rule, error) {
+		if len(args) != 1 {
+			return nil, errors.New("want 1 argument")
+		}
+		return func(errs Errors, form *multipart.Form, key string) {
+			if !sliceContainsString(form.Value[key], args[0]) {
+				errs.Add(key, &Error{Error: ErrInvalid})
+			}
+		}, nil
+	})
+	c, err := ParseChecker(map[string]string{"key": "required|foo:bar"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Errors{"key": {{Error: ErrInvalid}}}
+	if got := c.CheckValues(map[string][]string{"key": {"baz"}}); !reflect.DeepEqual(want, got) {
+		t.Errorf("Register: want %v, got %v", want, got)
+	}
+	if _, err = ParseRules("foo"); err == nil {
+		t.Error("Register: want factory error")
+	}
+}
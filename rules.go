@@ -1,6 +1,7 @@
 package check
 
 import (
+	"context"
 	"database/sql"
 	"mime/multipart"
 	"net/url"
@@ -8,6 +9,8 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/gowww/i18n"
 )
@@ -19,100 +22,186 @@ var (
 
 // A Rule is a checking function to be used inside a Checker.
 // It receives the errors map to add encountered errors, the whole form for relative checks, and the specific key to check.
+// When it checks the values (or files) of the key one by one, it should set the position of the failing ones in Error.Indexes.
 type Rule func(errs Errors, form *multipart.Form, key string)
 
 // Alpha rule checks that value contains alpha characters only.
+// Only ASCII letters are accepted: use AlphaUnicode for accented or non-latin letters.
 func Alpha(errs Errors, form *multipart.Form, key string) {
 	if form == nil && form.Value == nil {
 		return
 	}
-	for _, v := range form.Value[key] {
-		for i := 0; i < len(v); i++ {
-			if v[i] < 65 || v[i] > 90 && v[i] < 97 || v[i] > 122 {
-				errs.Add(key, &Error{Error: ErrNotAlpha})
-				return
+	for i, v := range form.Value[key] {
+		for j := 0; j < len(v); j++ {
+			if v[j] < 65 || v[j] > 90 && v[j] < 97 || v[j] > 122 {
+				errs.Add(key, &Error{Error: ErrNotAlpha, Indexes: []int{i}})
+				break
+			}
+		}
+	}
+}
+
+// AlphaUnicode rule checks that value contains Unicode letters only (with their combining marks, like accents), so "Zoë" passes.
+// With scripts (like unicode.Latin), letters must belong to one of them.
+func AlphaUnicode(scripts ...*unicode.RangeTable) Rule {
+	return func(errs Errors, form *multipart.Form, key string) {
+		if form == nil {
+			return
+		}
+		for i, v := range form.Value[key] {
+			if !isUnicodeAlpha(v, false, scripts) {
+				errs.Add(key, &Error{Error: ErrNotAlpha, Indexes: []int{i}})
 			}
 		}
 	}
 }
 
 // Alphanumeric rule checks that value contains alphaumeric characters only.
+// Only ASCII letters and digits are accepted: use AlphanumericUnicode for accented or non-latin letters.
 func Alphanumeric(errs Errors, form *multipart.Form, key string) {
 	if form == nil && form.Value == nil {
 		return
 	}
-	for _, v := range form.Value[key] {
-		for i := 0; i < len(v); i++ {
-			if v[i] < 48 || v[i] > 57 && v[i] < 65 || v[i] > 90 && v[i] < 97 || v[i] > 122 {
-				errs.Add(key, &Error{Error: ErrNotAlphanumeric})
-				return
+	for i, v := range form.Value[key] {
+		for j := 0; j < len(v); j++ {
+			if v[j] < 48 || v[j] > 57 && v[j] < 65 || v[j] > 90 && v[j] < 97 || v[j] > 122 {
+				errs.Add(key, &Error{Error: ErrNotAlphanumeric, Indexes: []int{i}})
+				break
 			}
 		}
 	}
 }
 
+// AlphanumericUnicode rule checks that value contains Unicode letters (with their combining marks, like accents) and decimal digits only.
+// With scripts (like unicode.Latin), letters must belong to one of them.
+func AlphanumericUnicode(scripts ...*unicode.RangeTable) Rule {
+	return func(errs Errors, form *multipart.Form, key string) {
+		if form == nil {
+			return
+		}
+		for i, v := range form.Value[key] {
+			if !isUnicodeAlpha(v, true, scripts) {
+				errs.Add(key, &Error{Error: ErrNotAlphanumeric, Indexes: []int{i}})
+			}
+		}
+	}
+}
+
+// Distinct rule checks that values are all different.
+func Distinct(errs Errors, form *multipart.Form, key string) {
+	if form == nil {
+		return
+	}
+	seen := make(map[string]bool, len(form.Value[key]))
+	for i, v := range form.Value[key] {
+		if seen[v] {
+			errs.Add(key, &Error{Error: ErrNotDistinct, Indexes: []int{i}})
+			continue
+		}
+		seen[v] = true
+	}
+}
+
 // Email rule checks that value represents an email.
 func Email(errs Errors, form *multipart.Form, key string) {
 	if form == nil && form.Value == nil {
 		return
 	}
-	for _, v := range form.Value[key] {
+	for i, v := range form.Value[key] {
 		if !reEmail.MatchString(v) {
-			errs.Add(key, &Error{Error: ErrNotEmail})
-			return
+			errs.Add(key, &Error{Error: ErrNotEmail, Indexes: []int{i}})
 		}
 	}
 }
 
 // FileType rule checks that file is one of given MIME types.
+// A file that cannot be read is a rule failure (see Errors.Fail).
 func FileType(types ...string) Rule {
 	return func(errs Errors, form *multipart.Form, key string) {
 		if form == nil && form.File == nil {
 			return
 		}
-		for _, file := range form.File[key] {
+		for i, file := range form.File[key] {
+			if file == nil {
+				continue
+			}
 			ct, err := fileType(file)
 			if err != nil {
-				continue
+				errs.Fail(key, err)
+				return
 			}
 			if !sliceContainsString(types, ct) {
-				errs.Add(key, &Error{Error: ErrBadFileType, Args: stringsToInterfaces(types)})
-				return
+				errs.Add(key, &Error{Error: ErrBadFileType, Args: stringsToInterfaces(types), Indexes: []int{i}})
 			}
 		}
 	}
 }
 
 // Image rule checks that file is GIF, JPEG or PNG.
+// A file that cannot be read is a rule failure (see Errors.Fail).
 func Image(errs Errors, form *multipart.Form, key string) {
 	if form == nil && form.File == nil {
 		return
 	}
-	for _, file := range form.File[key] {
+	for i, file := range form.File[key] {
+		if file == nil {
+			continue
+		}
 		ct, err := fileType(file)
 		if err != nil {
-			continue
+			errs.Fail(key, err)
+			return
 		}
 		if !sliceContainsString([]string{"image/gif", "image/jpeg", "image/png"}, ct) {
-			errs.Add(key, &Error{Error: ErrNotImage})
-			return
+			errs.Add(key, &Error{Error: ErrNotImage, Indexes: []int{i}})
 		}
 	}
 }
 
+// In rule checks that value is one of values.
+func In(values ...string) Rule {
+	return inRule(values, false)
+}
+
+// InDB rule checks that value exists in database, like a foreign key.
+// The placeholder ("?", "$1" or other) must be provided as it depends on the SQL driver.
+// As the accepted values are not listed, the error is ErrInvalid.
+// A database error is a rule failure (see Errors.Fail).
+// The query uses the context of the check, so it is cancelled with it.
+func InDB(db *sql.DB, table, column, placeholder string) Rule {
+	if db == nil {
+		panic(`check: no database provided for "indb" rule`)
+	}
+	return countRule(db, table, column, placeholder, ErrInvalid, func(n int) bool { return n == 0 })
+}
+
+// InFold rule works like In but ignores case (see strings.EqualFold).
+func InFold(values ...string) Rule {
+	return inRule(values, true)
+}
+
+// A ValuesFunc returns a list of values when a check needs it, like the choices of a select box stored in database.
+// It receives the context of the check.
+type ValuesFunc func(ctx context.Context) ([]string, error)
+
+// InFunc rule works like In with the values returned by f.
+// f is only called when key has values, and an error from f is a rule failure (see Errors.Fail).
+func InFunc(f ValuesFunc) Rule {
+	return funcRule(f, In)
+}
+
 // Integer rule checks that value represents an integer.
 func Integer(errs Errors, form *multipart.Form, key string) {
 	if form == nil && form.Value == nil {
 		return
 	}
-	for _, v := range form.Value[key] {
+	for i, v := range form.Value[key] {
 		if v == "." {
-			errs.Add(key, &Error{Error: ErrNotInteger})
-			return
+			errs.Add(key, &Error{Error: ErrNotInteger, Indexes: []int{i}})
+			continue
 		}
 		if _, err := strconv.Atoi(v); err != nil {
-			errs.Add(key, &Error{Error: ErrNotInteger})
-			return
+			errs.Add(key, &Error{Error: ErrNotInteger, Indexes: []int{i}})
 		}
 	}
 }
@@ -122,15 +211,14 @@ func Latitude(errs Errors, form *multipart.Form, key string) {
 	if form == nil && form.Value == nil {
 		return
 	}
-	for _, v := range form.Value[key] {
+	for i, v := range form.Value[key] {
 		f, err := strconv.ParseFloat(v, 64)
 		if err != nil {
-			errs.Add(key, &Error{Error: ErrNotNumber})
-			return
+			errs.Add(key, &Error{Error: ErrNotNumber, Indexes: []int{i}})
+			continue
 		}
 		if f < -90 || f > 90 {
-			errs.Add(key, &Error{Error: ErrNotLatitude})
-			return
+			errs.Add(key, &Error{Error: ErrNotLatitude, Indexes: []int{i}})
 		}
 	}
 }
@@ -140,15 +228,14 @@ func Longitude(errs Errors, form *multipart.Form, key string) {
 	if form == nil && form.Value == nil {
 		return
 	}
-	for _, v := range form.Value[key] {
+	for i, v := range form.Value[key] {
 		f, err := strconv.ParseFloat(v, 64)
 		if err != nil {
-			errs.Add(key, &Error{Error: ErrNotNumber})
-			return
+			errs.Add(key, &Error{Error: ErrNotNumber, Indexes: []int{i}})
+			continue
 		}
 		if f < -180 || f > 180 {
-			errs.Add(key, &Error{Error: ErrNotLongitude})
-			return
+			errs.Add(key, &Error{Error: ErrNotLongitude, Indexes: []int{i}})
 		}
 	}
 }
@@ -159,110 +246,178 @@ func Max(max float64) Rule {
 		if form == nil && form.Value == nil {
 			return
 		}
-		for _, v := range form.Value[key] {
+		for i, v := range form.Value[key] {
 			f, err := strconv.ParseFloat(v, 64)
 			if err != nil {
-				errs.Add(key, &Error{Error: ErrNotNumber})
-				return
+				errs.Add(key, &Error{Error: ErrNotNumber, Indexes: []int{i}})
+				continue
 			}
 			if f > max {
-				errs.Add(key, &Error{Error: ErrMax, Args: []interface{}{i18n.TransInt(max)}})
-				return
+				errs.Add(key, &Error{Error: ErrMax, Args: []interface{}{i18n.TransInt(max)}, Indexes: []int{i}})
 			}
 		}
 	}
 }
 
+// MaxCount rule checks that key has max or less values and files.
+func MaxCount(max int) Rule {
+	return func(errs Errors, form *multipart.Form, key string) {
+		if count(form, key) > max {
+			errs.Add(key, &Error{Error: ErrMaxCount, Args: []interface{}{i18n.TransInt(max)}})
+		}
+	}
+}
+
 // MaxFileSize rule checks if file has max or less bytes.
 func MaxFileSize(max int64) Rule {
 	return func(errs Errors, form *multipart.Form, key string) {
 		if form == nil && form.File == nil {
 			return
 		}
-		for _, file := range form.File[key] {
+		for i, file := range form.File[key] {
 			if file != nil && file.Size > max {
-				errs.Add(key, &Error{Error: ErrMaxFileSize, Args: []interface{}{i18n.TransFileSize(max)}})
-				return
+				errs.Add(key, &Error{Error: ErrMaxFileSize, Args: []interface{}{i18n.TransFileSize(max)}, Indexes: []int{i}})
 			}
 		}
 	}
 }
 
+// MaxGraphemeLen rule checks that value length, in user-perceived characters (see graphemeLen), is below or equals max.
+// So an emoji made of several code points counts as one character.
+func MaxGraphemeLen(max int) Rule {
+	return lenRule(graphemeLen, -1, max)
+}
+
 // MaxLen rule checks that value length is below or equals max.
+// Length is in bytes: use MaxRuneLen or MaxGraphemeLen to count characters.
 func MaxLen(max int) Rule {
 	return func(errs Errors, form *multipart.Form, key string) {
 		if form == nil && form.Value == nil {
 			return
 		}
-		for _, v := range form.Value[key] {
+		for i, v := range form.Value[key] {
 			if len(v) > max {
-				errs.Add(key, &Error{Error: ErrMaxLen, Args: []interface{}{i18n.TransInt(max)}})
-				return
+				errs.Add(key, &Error{Error: ErrMaxLen, Args: []interface{}{i18n.TransInt(max)}, Indexes: []int{i}})
 			}
 		}
 	}
 }
 
+// MaxRuneLen rule checks that value length, in Unicode code points, is below or equals max.
+// Unlike MaxLen which counts bytes, "é" is one character.
+func MaxRuneLen(max int) Rule {
+	return lenRule(utf8.RuneCountInString, -1, max)
+}
+
+// MaxTotalFileSize rule checks that all files of key have max or less bytes together.
+func MaxTotalFileSize(max int64) Rule {
+	return func(errs Errors, form *multipart.Form, key string) {
+		if form == nil {
+			return
+		}
+		var total int64
+		for _, file := range form.File[key] {
+			if file != nil {
+				total += file.Size
+			}
+		}
+		if total > max {
+			errs.Add(key, &Error{Error: ErrMaxTotalFileSize, Args: []interface{}{i18n.TransFileSize(max)}})
+		}
+	}
+}
+
 // Min rule checks that value is over or equals min.
 func Min(min float64) Rule {
 	return func(errs Errors, form *multipart.Form, key string) {
 		if form == nil && form.Value == nil {
 			return
 		}
-		for _, v := range form.Value[key] {
+		for i, v := range form.Value[key] {
 			f, err := strconv.ParseFloat(v, 64)
 			if err != nil {
-				errs.Add(key, &Error{Error: ErrNotNumber})
-				return
+				errs.Add(key, &Error{Error: ErrNotNumber, Indexes: []int{i}})
+				continue
 			}
 			if f < min {
-				errs.Add(key, &Error{Error: ErrMin, Args: []interface{}{i18n.TransInt(min)}})
-				return
+				errs.Add(key, &Error{Error: ErrMin, Args: []interface{}{i18n.TransInt(min)}, Indexes: []int{i}})
 			}
 		}
 	}
 }
 
+// MinCount rule checks that key has min or more values and files.
+func MinCount(min int) Rule {
+	return func(errs Errors, form *multipart.Form, key string) {
+		if count(form, key) < min {
+			errs.Add(key, &Error{Error: ErrMinCount, Args: []interface{}{i18n.TransInt(min)}})
+		}
+	}
+}
+
 // MinFileSize rule checks if file has min or more bytes.
 func MinFileSize(min int64) Rule {
 	return func(errs Errors, form *multipart.Form, key string) {
 		if form == nil && form.File == nil {
 			return
 		}
-		for _, file := range form.File[key] {
+		for i, file := range form.File[key] {
 			if file != nil && file.Size < min {
-				errs.Add(key, &Error{Error: ErrMinFileSize, Args: []interface{}{i18n.TransFileSize(min)}})
-				return
+				errs.Add(key, &Error{Error: ErrMinFileSize, Args: []interface{}{i18n.TransFileSize(min)}, Indexes: []int{i}})
 			}
 		}
 	}
 }
 
+// MinGraphemeLen rule checks that value length, in user-perceived characters (see MaxGraphemeLen), is over or equals min.
+func MinGraphemeLen(min int) Rule {
+	return lenRule(graphemeLen, min, -1)
+}
+
 // MinLen rule checks that value length is over or equals min.
 func MinLen(min int) Rule {
 	return func(errs Errors, form *multipart.Form, key string) {
 		if form == nil && form.Value == nil {
 			return
 		}
-		for _, v := range form.Value[key] {
+		for i, v := range form.Value[key] {
 			if len(v) < min {
-				errs.Add(key, &Error{Error: ErrMinLen, Args: []interface{}{i18n.TransInt(min)}})
-				return
+				errs.Add(key, &Error{Error: ErrMinLen, Args: []interface{}{i18n.TransInt(min)}, Indexes: []int{i}})
 			}
 		}
 	}
 }
 
+// MinRuneLen rule checks that value length, in Unicode code points, is over or equals min.
+func MinRuneLen(min int) Rule {
+	return lenRule(utf8.RuneCountInString, min, -1)
+}
+
+// NotIn rule checks that value is none of values.
+func NotIn(values ...string) Rule {
+	return notInRule(values, false)
+}
+
+// NotInFold rule works like NotIn but ignores case (see strings.EqualFold).
+func NotInFold(values ...string) Rule {
+	return notInRule(values, true)
+}
+
+// NotInFunc rule works like NotIn with the values returned by f.
+// f is only called when key has values, and an error from f is a rule failure (see Errors.Fail).
+func NotInFunc(f ValuesFunc) Rule {
+	return funcRule(f, NotIn)
+}
+
 // Number rule checks that value represents a number.
 func Number(errs Errors, form *multipart.Form, key string) {
 	if form == nil && form.Value == nil {
 		return
 	}
-	for _, v := range form.Value[key] {
+	for i, v := range form.Value[key] {
 		_, err := strconv.ParseFloat(v, 64)
 		if err != nil {
-			errs.Add(key, &Error{Error: ErrNotNumber})
-			return
+			errs.Add(key, &Error{Error: ErrNotNumber, Indexes: []int{i}})
 		}
 	}
 }
@@ -272,10 +427,9 @@ func Phone(errs Errors, form *multipart.Form, key string) {
 	if form == nil && form.Value == nil {
 		return
 	}
-	for _, v := range form.Value[key] {
+	for i, v := range form.Value[key] {
 		if !rePhone.MatchString(v) {
-			errs.Add(key, &Error{Error: ErrNotPhone})
-			return
+			errs.Add(key, &Error{Error: ErrNotPhone, Indexes: []int{i}})
 		}
 	}
 }
@@ -286,88 +440,133 @@ func Range(min, max float64) Rule {
 		if form == nil && form.Value == nil {
 			return
 		}
-		for _, v := range form.Value[key] {
+		for i, v := range form.Value[key] {
 			f, err := strconv.ParseFloat(v, 64)
 			if err != nil {
-				errs.Add(key, &Error{Error: ErrNotNumber})
-				return
+				errs.Add(key, &Error{Error: ErrNotNumber, Indexes: []int{i}})
+				continue
 			}
 			if f > max {
-				errs.Add(key, &Error{Error: ErrMax, Args: []interface{}{i18n.TransInt(max)}})
-				return
+				errs.Add(key, &Error{Error: ErrMax, Args: []interface{}{i18n.TransInt(max)}, Indexes: []int{i}})
+				continue
 			}
 			if f < min {
-				errs.Add(key, &Error{Error: ErrMin, Args: []interface{}{i18n.TransInt(min)}})
-				return
+				errs.Add(key, &Error{Error: ErrMin, Args: []interface{}{i18n.TransInt(min)}, Indexes: []int{i}})
 			}
 		}
 	}
 }
 
+// RangeCount rule checks that the number of values and files of key is inside a range.
+func RangeCount(min, max int) Rule {
+	return func(errs Errors, form *multipart.Form, key string) {
+		if n := count(form, key); n > max {
+			errs.Add(key, &Error{Error: ErrMaxCount, Args: []interface{}{i18n.TransInt(max)}})
+		} else if n < min {
+			errs.Add(key, &Error{Error: ErrMinCount, Args: []interface{}{i18n.TransInt(min)}})
+		}
+	}
+}
+
 // RangeFileSize rule checks if file length is inside a range.
 func RangeFileSize(min, max int64) Rule {
 	return func(errs Errors, form *multipart.Form, key string) {
 		if form == nil && form.File == nil {
 			return
 		}
-		for _, file := range form.File[key] {
+		for i, file := range form.File[key] {
 			if file == nil {
 				continue
 			}
 			if file.Size > max {
-				errs.Add(key, &Error{Error: ErrMaxFileSize, Args: []interface{}{i18n.TransFileSize(max)}})
-				return
+				errs.Add(key, &Error{Error: ErrMaxFileSize, Args: []interface{}{i18n.TransFileSize(max)}, Indexes: []int{i}})
+				continue
 			}
 			if file.Size < min {
-				errs.Add(key, &Error{Error: ErrMinFileSize, Args: []interface{}{i18n.TransFileSize(min)}})
-				return
+				errs.Add(key, &Error{Error: ErrMinFileSize, Args: []interface{}{i18n.TransFileSize(min)}, Indexes: []int{i}})
 			}
 		}
 	}
 }
 
+// RangeGraphemeLen rule checks that value length, in user-perceived characters (see MaxGraphemeLen), is inside a range.
+func RangeGraphemeLen(min, max int) Rule {
+	return lenRule(graphemeLen, min, max)
+}
+
 // RangeLen rule checks that value length is inside a range.
 func RangeLen(min, max int) Rule {
 	return func(errs Errors, form *multipart.Form, key string) {
 		if form == nil && form.Value == nil {
 			return
 		}
-		for _, v := range form.Value[key] {
+		for i, v := range form.Value[key] {
 			if len(v) > max {
-				errs.Add(key, &Error{Error: ErrMaxLen, Args: []interface{}{i18n.TransInt(max)}})
-				return
+				errs.Add(key, &Error{Error: ErrMaxLen, Args: []interface{}{i18n.TransInt(max)}, Indexes: []int{i}})
+				continue
 			}
 			if len(v) < min {
-				errs.Add(key, &Error{Error: ErrMinLen, Args: []interface{}{i18n.TransInt(min)}})
-				return
+				errs.Add(key, &Error{Error: ErrMinLen, Args: []interface{}{i18n.TransInt(min)}, Indexes: []int{i}})
 			}
 		}
 	}
 }
 
+// RangeRuneLen rule checks that value length, in Unicode code points, is inside a range.
+func RangeRuneLen(min, max int) Rule {
+	return lenRule(utf8.RuneCountInString, min, max)
+}
+
 // Required rule checks that value or file exists and is not empty.
-// A value is not trimmed so a single space can pass the check.
+// A value is not trimmed so a single space can pass the check (use the Trim sanitizer before to avoid it).
 func Required(errs Errors, form *multipart.Form, key string) {
-	if form == nil {
+	if !filled(form, key) {
 		errs.Add(key, &Error{Error: ErrRequired})
-		return
 	}
-	if form.Value != nil {
-		for _, v := range form.Value[key] {
-			if v != "" {
+}
+
+// RequiredIf rule checks that value or file exists and is not empty (like Required), if the other key has one of the given values.
+// Without values, the other key must only be filled to require this one.
+func RequiredIf(other string, values ...string) Rule {
+	return func(errs Errors, form *multipart.Form, key string) {
+		if hasOneOf(form, other, values) {
+			Required(errs, form, key)
+		}
+	}
+}
+
+// RequiredUnless rule checks that value or file exists and is not empty (like Required), unless the other key has one of the given values.
+// Without values, the other key must only be filled to not require this one.
+func RequiredUnless(other string, values ...string) Rule {
+	return func(errs Errors, form *multipart.Form, key string) {
+		if !hasOneOf(form, other, values) {
+			Required(errs, form, key)
+		}
+	}
+}
+
+// RequiredWith rule checks that value or file exists and is not empty (like Required), if one of the other keys is filled.
+func RequiredWith(others ...string) Rule {
+	return func(errs Errors, form *multipart.Form, key string) {
+		for _, other := range others {
+			if filled(form, other) {
+				Required(errs, form, key)
 				return
 			}
 		}
 	}
-	if form.File != nil {
-		for _, v := range form.File[key] {
-			if v == nil {
+}
+
+// RequiredWithout rule checks that value or file exists and is not empty (like Required), if one of the other keys is not filled.
+func RequiredWithout(others ...string) Rule {
+	return func(errs Errors, form *multipart.Form, key string) {
+		for _, other := range others {
+			if !filled(form, other) {
+				Required(errs, form, key)
 				return
 			}
 		}
 	}
-	errs.Add(key, &Error{Error: ErrRequired})
-	return
 }
 
 // Same rule checks that value deeply equals another key value.
@@ -387,25 +586,13 @@ func Same(keys ...string) Rule {
 
 // Unique rule checks that value is unique in database.
 // The placeholder ("?", "$1" or other) must be provided as it depends on the SQL driver.
+// A database error is a rule failure (see Errors.Fail).
+// The query uses the context of the check, so it is cancelled with it.
 func Unique(db *sql.DB, table, column, placeholder string) Rule {
 	if db == nil {
 		panic(`check: no database provided for "unique" rule`)
 	}
-	return func(errs Errors, form *multipart.Form, key string) {
-		if _, ok := errs[key]; ok { // Avoid a database call if the format is already bad.
-			return
-		}
-		for _, v := range form.Value[key] {
-			var n int
-			if err := db.QueryRow("SELECT COUNT() FROM "+table+" WHERE "+column+" = "+placeholder, v).Scan(&n); err != nil {
-				panic(err)
-			}
-			if n > 0 {
-				errs.Add(key, &Error{Error: ErrNotUnique})
-				return
-			}
-		}
-	}
+	return countRule(db, table, column, placeholder, ErrNotUnique, func(n int) bool { return n > 0 })
 }
 
 // URL rule checks that value represents an URL.
@@ -413,27 +600,27 @@ func URL(errs Errors, form *multipart.Form, key string) {
 	if form == nil && form.Value == nil {
 		return
 	}
-	for _, v := range form.Value[key] {
+	for i, v := range form.Value[key] {
 		if len(v) < 4 {
-			errs.Add(key, &Error{Error: ErrNotURL})
-			return
+			errs.Add(key, &Error{Error: ErrNotURL, Indexes: []int{i}})
+			continue
 		}
 		v = strings.Replace(v, "127.0.0.1", "localhost", 1)
-		if i := strings.IndexByte(v, '#'); i != -1 {
-			v = v[:i]
+		if j := strings.IndexByte(v, '#'); j != -1 {
+			v = v[:j]
 		}
 		if !strings.Contains(v, "://") {
 			v = "http://" + v
 		}
 		u, err := url.ParseRequestURI(v)
 		if err != nil || u.Host == "" || u.Host[0] == '-' || strings.Contains(u.Host, ".-") || strings.Contains(u.Host, "-.") {
-			errs.Add(key, &Error{Error: ErrNotURL})
-			return
+			errs.Add(key, &Error{Error: ErrNotURL, Indexes: []int{i}})
+			continue
 		}
 		parts := strings.Split(u.Host, ".")
 		if parts[0] == "" {
-			errs.Add(key, &Error{Error: ErrNotURL})
-			return
+			errs.Add(key, &Error{Error: ErrNotURL, Indexes: []int{i}})
+			continue
 		}
 		var domain string
 		if len(parts) > 2 {
@@ -442,18 +629,206 @@ func URL(errs Errors, form *multipart.Form, key string) {
 			domain = strings.Join(parts, ".")
 		}
 		if strings.ContainsAny(domain, "_,&") {
-			errs.Add(key, &Error{Error: ErrNotURL})
-			return
+			errs.Add(key, &Error{Error: ErrNotURL, Indexes: []int{i}})
+			continue
 		}
 		if strings.Count(domain, "::") > 1 { // Only 1 substitution ("::") allowed in IPv6 address.
-			errs.Add(key, &Error{Error: ErrNotURL})
-			return
+			errs.Add(key, &Error{Error: ErrNotURL, Indexes: []int{i}})
+			continue
 		}
 		parts = strings.Split(domain, ":")
 		port, err := strconv.Atoi(parts[len(parts)-1])
 		if err == nil && (port < 1 || port > 65535) {
-			errs.Add(key, &Error{Error: ErrNotURL})
+			errs.Add(key, &Error{Error: ErrNotURL, Indexes: []int{i}})
+		}
+	}
+}
+
+func inRule(values []string, fold bool) Rule {
+	return func(errs Errors, form *multipart.Form, key string) {
+		if form == nil {
+			return
+		}
+		for i, v := range form.Value[key] {
+			if !containsValue(values, v, fold) {
+				errs.Add(key, &Error{Error: ErrNotIn, Args: stringsToInterfaces(values), Indexes: []int{i}})
+			}
+		}
+	}
+}
+
+func notInRule(values []string, fold bool) Rule {
+	return func(errs Errors, form *multipart.Form, key string) {
+		if form == nil {
 			return
 		}
+		for i, v := range form.Value[key] {
+			if containsValue(values, v, fold) {
+				errs.Add(key, &Error{Error: ErrNotAllowed, Indexes: []int{i}})
+			}
+		}
+	}
+}
+
+// funcRule returns a Rule applying the rule made by newRule with the values of f.
+func funcRule(f ValuesFunc, newRule func(...string) Rule) Rule {
+	return WithContext(func(ctx context.Context, errs Errors, form *multipart.Form, key string) {
+		if form == nil || len(form.Value[key]) == 0 {
+			return
+		}
+		values, err := f(ctx)
+		if err != nil {
+			if ctx.Err() == nil { // No failure when check is cancelled.
+				errs.Fail(key, err)
+			}
+			return
+		}
+		newRule(values...)(errs, form, key)
+	})
+}
+
+// countRule returns a rule counting the rows of table where column equals each value, with the context of the check.
+// The errID error is set for the values whose count n makes fails(n) true.
+// A database error is a rule failure (see Errors.Fail), except when the check is cancelled.
+func countRule(db *sql.DB, table, column, placeholder string, errID *ErrorID, fails func(n int) bool) Rule {
+	return WithContext(func(ctx context.Context, errs Errors, form *multipart.Form, key string) {
+		if _, ok := errs[key]; ok { // Avoid a database call if the format is already bad.
+			return
+		}
+		for i, v := range form.Value[key] {
+			var n int
+			if err := db.QueryRowContext(ctx, "SELECT COUNT() FROM "+table+" WHERE "+column+" = "+placeholder, v).Scan(&n); err != nil {
+				if ctx.Err() == nil { // No failure when check is cancelled.
+					errs.Fail(key, err)
+				}
+				return
+			}
+			if fails(n) {
+				errs.Add(key, &Error{Error: errID, Indexes: []int{i}})
+			}
+		}
+	})
+}
+
+// containsValue tells if values contains v, ignoring case if fold is true.
+func containsValue(values []string, v string, fold bool) bool {
+	for _, value := range values {
+		if value == v || fold && strings.EqualFold(value, v) {
+			return true
+		}
+	}
+	return false
+}
+
+// filled tells if key has a non-empty value or a file.
+func filled(form *multipart.Form, key string) bool {
+	if form == nil {
+		return false
+	}
+	for _, v := range form.Value[key] {
+		if v != "" {
+			return true
+		}
+	}
+	for _, v := range form.File[key] {
+		if v != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// lenRule returns a rule checking that value length, measured by length, is inside a range.
+// A negative bound is not checked.
+func lenRule(length func(string) int, min, max int) Rule {
+	return func(errs Errors, form *multipart.Form, key string) {
+		if form == nil {
+			return
+		}
+		for i, v := range form.Value[key] {
+			n := length(v)
+			if max >= 0 && n > max {
+				errs.Add(key, &Error{Error: ErrMaxLen, Args: []interface{}{i18n.TransInt(max)}, Indexes: []int{i}})
+			} else if min >= 0 && n < min {
+				errs.Add(key, &Error{Error: ErrMinLen, Args: []interface{}{i18n.TransInt(min)}, Indexes: []int{i}})
+			}
+		}
+	}
+}
+
+// isUnicodeAlpha tells if s contains letters only (or also digits if digits is true).
+// Combining marks are accepted after a letter or a digit.
+// With scripts, letters must belong to one of them.
+func isUnicodeAlpha(s string, digits bool, scripts []*unicode.RangeTable) bool {
+	prev := false // Previous rune is accepted and can have marks.
+	for _, r := range s {
+		switch {
+		case unicode.IsLetter(r):
+			if len(scripts) > 0 && !unicode.In(r, scripts...) {
+				return false
+			}
+		case digits && unicode.IsDigit(r):
+		case prev && unicode.IsMark(r):
+			continue
+		default:
+			return false
+		}
+		prev = true
+	}
+	return true
+}
+
+// graphemeLen returns the number of user-perceived characters in s.
+// It approximates the extended grapheme clusters of Unicode: combining marks, variation selectors and emoji modifiers are counted with their base character,
+// a zero width joiner joins the next character to the previous one (like in "👩‍💻") and regional indicators are counted by pairs (for flags).
+func graphemeLen(s string) int {
+	n := 0
+	joined := false   // Next rune is joined to the previous one.
+	regional := false // Previous rune is a single regional indicator.
+	for _, r := range s {
+		switch {
+		case r == '\u200d': // Zero width joiner.
+			joined = n > 0
+			continue
+		case joined:
+			joined = false
+			continue
+		case unicode.IsMark(r), r >= 0x1f3fb && r <= 0x1f3ff: // Combining marks (including variation selectors) and emoji modifiers.
+			if n > 0 {
+				continue
+			}
+		case r >= 0x1f1e6 && r <= 0x1f1ff: // Regional indicators.
+			if regional {
+				regional = false
+				continue
+			}
+			regional = true
+			n++
+			continue
+		}
+		regional = false
+		n++
+	}
+	return n
+}
+
+// count returns the number of values and files of key.
+func count(form *multipart.Form, key string) int {
+	if form == nil {
+		return 0
+	}
+	return len(form.Value[key]) + len(form.File[key])
+}
+
+// hasOneOf tells if key has one of values or, if there are no values, if key is filled.
+func hasOneOf(form *multipart.Form, key string, values []string) bool {
+	if form == nil || len(values) == 0 {
+		return filled(form, key)
+	}
+	for _, v := range form.Value[key] {
+		if sliceContainsString(values, v) {
+			return true
+		}
 	}
+	return false
 }
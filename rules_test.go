@@ -1,5 +1,16 @@
 package check
 
+import (
+	"context"
+	"errors"
+	"mime/multipart"
+	"reflect"
+	"testing"
+	"unicode"
+
+	"github.com/gowww/i18n"
+)
+
 /*
 import (
 	"mime/multipart"
@@ -258,3 +269,185 @@ func TestURL(t *testing.T) {
 	})
 }
 */
+
+func TestRequiredConditions(t *testing.T) {
+	values := map[string][]string{
+		"country": {"FR"},
+		"email":   {"foo@example.com"},
+		"empty":   {""},
+	}
+	cases := []struct {
+		rule Rule
+		want bool
+	}{
+		{RequiredIf("country", "FR", "DE"), true},
+		{RequiredIf("country", "US"), false},
+		{RequiredIf("country"), true},
+		{RequiredIf("empty"), false},
+		{RequiredUnless("country", "FR"), false},
+		{RequiredUnless("country", "US"), true},
+		{RequiredUnless("missing"), true},
+		{RequiredWith("missing", "email"), true},
+		{RequiredWith("missing", "empty"), false},
+		{RequiredWithout("email"), false},
+		{RequiredWithout("email", "empty"), true},
+	}
+	for i, c := range cases {
+		errs := make(Errors)
+		errs.Add("vat", &Error{Error: ErrInvalid})
+		c.rule(errs, &multipart.Form{Value: values}, "vat")
+		want := Errors{"vat": {{Error: ErrInvalid}}}
+		if c.want {
+			want = Errors{"vat": {{Error: ErrRequired}}}
+		}
+		if !reflect.DeepEqual(want, errs) {
+			t.Errorf("case %d: want %v, got %v", i, want, errs)
+		}
+	}
+}
+
+func TestCardinality(t *testing.T) {
+	form := &multipart.Form{
+		Value: map[string][]string{
+			"tags": {"a", "b", "a"},
+		},
+		File: map[string][]*multipart.FileHeader{
+			"pictures": {{Size: 600}, {Size: 500}, nil},
+		},
+	}
+	cases := []struct {
+		rule Rule
+		key  string
+		want []*Error
+	}{
+		{MinCount(3), "tags", nil},
+		{MinCount(4), "tags", []*Error{{Error: ErrMinCount, Args: []interface{}{i18n.TransInt(4)}}}},
+		{MinCount(1), "missing", []*Error{{Error: ErrMinCount, Args: []interface{}{i18n.TransInt(1)}}}},
+		{MaxCount(3), "pictures", nil},
+		{MaxCount(2), "tags", []*Error{{Error: ErrMaxCount, Args: []interface{}{i18n.TransInt(2)}}}},
+		{RangeCount(1, 3), "tags", nil},
+		{RangeCount(1, 2), "pictures", []*Error{{Error: ErrMaxCount, Args: []interface{}{i18n.TransInt(2)}}}},
+		{RangeCount(1, 2), "missing", []*Error{{Error: ErrMinCount, Args: []interface{}{i18n.TransInt(1)}}}},
+		{Distinct, "tags", []*Error{{Error: ErrNotDistinct, Indexes: []int{2}}}},
+		{Distinct, "missing", nil},
+		{MaxTotalFileSize(1100), "pictures", nil},
+		{MaxTotalFileSize(1000), "pictures", []*Error{{Error: ErrMaxTotalFileSize, Args: []interface{}{i18n.TransFileSize(1000)}}}},
+	}
+	for i, c := range cases {
+		errs := make(Errors)
+		c.rule(errs, form, c.key)
+		if !reflect.DeepEqual(c.want, errs[c.key]) {
+			t.Errorf("case %d: want %v, got %v", i, c.want, errs[c.key])
+		}
+	}
+	errs := make(Errors)
+	Distinct(errs, &multipart.Form{Value: map[string][]string{"tags": {"a", "b"}}}, "tags")
+	if errs.NotEmpty() {
+		t.Errorf("Distinct: want no error, got %v", errs)
+	}
+}
+
+func TestUnicodeRules(t *testing.T) {
+	cases := []struct {
+		rule  Rule
+		value string
+		want  *ErrorID
+	}{
+		{AlphaUnicode(), "Zoë", nil},
+		{AlphaUnicode(), "Zoe\u0308", nil},
+		{AlphaUnicode(), "東京", nil},
+		{AlphaUnicode(), "Zoë2", ErrNotAlpha},
+		{AlphaUnicode(), "\u0308a", ErrNotAlpha},
+		{AlphaUnicode(unicode.Latin), "Zoë", nil},
+		{AlphaUnicode(unicode.Latin), "Зоя", ErrNotAlpha},
+		{AlphanumericUnicode(), "Zoë2", nil},
+		{AlphanumericUnicode(unicode.Latin), "Zoë 2", ErrNotAlphanumeric},
+		{MaxRuneLen(3), "Zoë", nil},
+		{MaxRuneLen(2), "Zoë", ErrMaxLen},
+		{MinRuneLen(4), "Zoë", ErrMinLen},
+		{RangeRuneLen(1, 2), "東京", nil},
+		{MaxGraphemeLen(3), "Zoe\u0308", nil},
+		{MaxRuneLen(3), "Zoe\u0308", ErrMaxLen},
+		{MaxGraphemeLen(1), "👩‍💻", nil},
+		{MaxGraphemeLen(1), "👍🏽", nil},
+		{MaxGraphemeLen(2), "🇫🇷🇯🇵", nil},
+		{MaxGraphemeLen(1), "🇫🇷🇯🇵", ErrMaxLen},
+		{MinGraphemeLen(4), "Zoe\u0308", ErrMinLen},
+		{RangeGraphemeLen(2, 3), "Zoë", nil},
+	}
+	for i, c := range cases {
+		errs := make(Errors)
+		c.rule(errs, &multipart.Form{Value: map[string][]string{"name": {c.value}}}, "name")
+		var got *ErrorID
+		if e := errs.First("name"); e != nil {
+			got = e.Error
+		}
+		if got != c.want {
+			t.Errorf("case %d (%q): want %v, got %v", i, c.value, c.want, errs)
+		}
+	}
+}
+
+func TestEnumRules(t *testing.T) {
+	form := &multipart.Form{
+		Value: map[string][]string{
+			"status": {"draft", "Published", "deleted"},
+		},
+	}
+	statuses := []interface{}{"draft", "published"}
+	cases := []struct {
+		rule Rule
+		want []*Error
+	}{
+		{In("draft", "published"), []*Error{{Error: ErrNotIn, Args: statuses, Indexes: []int{1, 2}}}},
+		{InFold("draft", "published"), []*Error{{Error: ErrNotIn, Args: statuses, Indexes: []int{2}}}},
+		{InFold("DRAFT", "published", "deleted"), nil},
+		{NotIn("deleted", "published"), []*Error{{Error: ErrNotAllowed, Indexes: []int{2}}}},
+		{NotInFold("deleted", "published"), []*Error{{Error: ErrNotAllowed, Indexes: []int{1, 2}}}},
+		{NotInFold("archived"), nil},
+	}
+	for i, c := range cases {
+		errs := make(Errors)
+		c.rule(errs, form, "status")
+		if !reflect.DeepEqual(c.want, errs["status"]) {
+			t.Errorf("case %d:\nwant %v\ngot  %v", i, c.want, errs["status"])
+		}
+	}
+}
+
+func TestEnumFuncRules(t *testing.T) {
+	var calls int
+	statuses := func(ctx context.Context) ([]string, error) {
+		calls++
+		if ctx.Value(testContextKey{}) == nil {
+			return nil, errors.New("no context value")
+		}
+		return []string{"draft", "published"}, nil
+	}
+	c := Checker{
+		"status":   {InFunc(statuses)},
+		"previous": {NotInFunc(statuses)},
+	}
+	ctx := context.WithValue(context.Background(), testContextKey{}, "foo")
+
+	errs, err := c.CheckContext(ctx, &multipart.Form{Value: map[string][]string{"status": {"draft", "deleted"}, "previous": {"draft"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Errors{
+		"status":   {{Error: ErrNotIn, Args: []interface{}{"draft", "published"}, Indexes: []int{1}}},
+		"previous": {{Error: ErrNotAllowed, Indexes: []int{0}}},
+	}
+	if !reflect.DeepEqual(want, errs) {
+		t.Errorf("InFunc:\nwant %v\ngot  %v", want, errs)
+	}
+
+	calls = 0
+	if _, err := c.CheckContext(ctx, &multipart.Form{}); err != nil || calls != 0 {
+		t.Errorf("InFunc: want no call without values, got %d calls and error %v", calls, err)
+	}
+
+	if _, err := c.CheckE(&multipart.Form{Value: map[string][]string{"status": {"draft"}}}); err == nil {
+		t.Error("InFunc: want *RuleError for a provider error, got nil")
+	}
+}
@@ -0,0 +1,124 @@
+package check
+
+import (
+	"mime/multipart"
+	"reflect"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Sanitizers are rules that change the values of a key instead of checking them.
+// They can be placed anywhere in the rules of a key, and following rules receive the changed values.
+// Use Checker.CheckClean to get the changed values after the check.
+
+// CollapseSpace sanitizer trims value and replaces each sequence of white spaces by a single space.
+func CollapseSpace(errs Errors, form *multipart.Form, key string) {
+	sanitize(form, key, func(v string) string {
+		return strings.Join(strings.Fields(v), " ")
+	})
+}
+
+// Lower sanitizer maps value to its lower case.
+func Lower(errs Errors, form *multipart.Form, key string) {
+	sanitize(form, key, strings.ToLower)
+}
+
+// NFC sanitizer normalizes value to the Unicode Normalization Form C, so a same text has always the same bytes.
+func NFC(errs Errors, form *multipart.Form, key string) {
+	sanitize(form, key, norm.NFC.String)
+}
+
+// NormalizeEmail sanitizer trims value and maps it to its lower case.
+func NormalizeEmail(errs Errors, form *multipart.Form, key string) {
+	sanitize(form, key, func(v string) string {
+		return strings.ToLower(strings.TrimSpace(v))
+	})
+}
+
+// PhoneDigits sanitizer removes all characters except digits from value, keeping a leading "+".
+func PhoneDigits(errs Errors, form *multipart.Form, key string) {
+	sanitize(form, key, func(v string) string {
+		v = strings.TrimSpace(v)
+		plus := strings.HasPrefix(v, "+")
+		v = strings.Map(func(r rune) rune {
+			if r >= '0' && r <= '9' {
+				return r
+			}
+			return -1
+		}, v)
+		if plus {
+			return "+" + v
+		}
+		return v
+	})
+}
+
+// StripControl sanitizer removes control characters from value, except tabulations and line breaks.
+func StripControl(errs Errors, form *multipart.Form, key string) {
+	sanitize(form, key, func(v string) string {
+		return strings.Map(func(r rune) rune {
+			if unicode.IsControl(r) && r != '\t' && r != '\n' && r != '\r' {
+				return -1
+			}
+			return r
+		}, v)
+	})
+}
+
+// Trim sanitizer removes leading and trailing white spaces from value.
+func Trim(errs Errors, form *multipart.Form, key string) {
+	sanitize(form, key, strings.TrimSpace)
+}
+
+// sanitize replaces each value of key by f(value).
+// The values slice is changed in place, so it must not be read concurrently: that's why a parallel check applies the rules of a key with sanitizers alone (see checkParallel).
+// Rules wrapping other rules (like Each or Nested) are marked with sanitizerOf for this.
+func sanitize(form *multipart.Form, key string, f func(string) string) {
+	if form == nil {
+		return
+	}
+	vv := form.Value[key]
+	for i, v := range vv {
+		vv[i] = f(v)
+	}
+}
+
+var sanitizerPointers = map[uintptr]bool{
+	reflect.ValueOf(CollapseSpace).Pointer():  true,
+	reflect.ValueOf(Lower).Pointer():          true,
+	reflect.ValueOf(NFC).Pointer():            true,
+	reflect.ValueOf(NormalizeEmail).Pointer(): true,
+	reflect.ValueOf(PhoneDigits).Pointer():    true,
+	reflect.ValueOf(StripControl).Pointer():   true,
+	reflect.ValueOf(Trim).Pointer():           true,
+}
+
+// markedSanitizerPointer is the pointer of the rules returned by markSanitizer.
+var markedSanitizerPointer = reflect.ValueOf(markSanitizer(nil)).Pointer()
+
+// sanitizes tells if rules have a sanitizer of this package (or a rule wrapping one, see sanitizerOf), so they change the values of their key.
+func sanitizes(rules []Rule) bool {
+	for _, rule := range rules {
+		if p := reflect.ValueOf(rule).Pointer(); sanitizerPointers[p] || p == markedSanitizerPointer {
+			return true
+		}
+	}
+	return false
+}
+
+// sanitizerOf returns rule, marked as a sanitizer if rules (the ones it applies) have one.
+func sanitizerOf(rule Rule, rules []Rule) Rule {
+	if sanitizes(rules) {
+		return markSanitizer(rule)
+	}
+	return rule
+}
+
+// markSanitizer returns a rule applying rule, that sanitizes reports as a sanitizer.
+func markSanitizer(rule Rule) Rule {
+	return func(errs Errors, form *multipart.Form, key string) {
+		rule(errs, form, key)
+	}
+}
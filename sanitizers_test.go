@@ -0,0 +1,56 @@
+package check
+
+import (
+	"mime/multipart"
+	"reflect"
+	"testing"
+)
+
+func TestSanitizers(t *testing.T) {
+	cases := []struct {
+		rule Rule
+		v    string
+		want string
+	}{
+		{CollapseSpace, "  foo \t bar\n baz ", "foo bar baz"},
+		{Lower, "FoO", "foo"},
+		{NFC, "Zoe\u0308", "Zo\u00eb"},
+		{NormalizeEmail, " Foo@Example.COM ", "foo@example.com"},
+		{PhoneDigits, " +33 (0)6.12-34 ", "+33061234"},
+		{PhoneDigits, "06 12", "0612"},
+		{StripControl, "foo\x00\x1bbar\r\n\tbaz", "foobar\r\n\tbaz"},
+		{Trim, " \tfoo bar\n", "foo bar"},
+	}
+	for _, c := range cases {
+		form := &multipart.Form{Value: map[string][]string{"key": {c.v}}}
+		c.rule(nil, form, "key")
+		if got := form.Value["key"][0]; got != c.want {
+			t.Errorf("sanitizer(%q): want %q, got %q", c.v, c.want, got)
+		}
+	}
+}
+
+func TestCheckerCheckClean(t *testing.T) {
+	c := Checker{
+		"email": {Trim, Required, NormalizeEmail, Email},
+		"name":  {Trim, Required},
+		"tags":  {Each(Trim, Lower, Alpha)},
+	}
+	form := &multipart.Form{Value: map[string][]string{
+		"email": {" Foo@Example.com"},
+		"name":  {" "},
+		"tags":  {" Foo", "bar "},
+		"other": {" bar "},
+	}}
+	clean, errs := c.CheckClean(form)
+	if want := (Errors{"name": {{Error: ErrRequired}}}); !reflect.DeepEqual(want, errs) {
+		t.Errorf("Checker.CheckClean errors: want %v, got %v", want, errs)
+	}
+	want := map[string][]string{"email": {"foo@example.com"}, "name": {""}, "tags": {"foo", "bar"}, "other": {" bar "}}
+	if !reflect.DeepEqual(want, clean.Value) {
+		t.Errorf("Checker.CheckClean values: want %v, got %v", want, clean.Value)
+	}
+	if form.Value["email"][0] != " Foo@Example.com" {
+		t.Errorf("Checker.CheckClean: original form changed")
+	}
+}
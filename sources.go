@@ -0,0 +1,91 @@
+package check
+
+import (
+	"mime/multipart"
+	"net/http"
+	"strings"
+)
+
+// Request sources that can qualify a checker key, like "query:page" or "header:X-Request-ID".
+const (
+	sourceQuery  = "query"
+	sourceBody   = "body"
+	sourceHeader = "header"
+	sourceCookie = "cookie"
+	sourcePath   = "path"
+)
+
+// sourceKey returns the source and the name of a qualified key (like "query" and "page" for "query:page").
+// For an unqualified key, the source is empty.
+func sourceKey(key string) (source, name string) {
+	i := strings.IndexByte(key, ':')
+	if i == -1 {
+		return "", key
+	}
+	switch source = key[:i]; source {
+	case sourceQuery, sourceBody, sourceHeader, sourceCookie, sourcePath:
+		return source, key[i+1:]
+	}
+	return "", key
+}
+
+// addSourceValues sets the values of the source qualified keys in form, from r and its body values.
+// Form values and files are copied before, so the maps of r are never changed.
+func addSourceValues(form *multipart.Form, r *http.Request, body map[string][]string, keys []string) {
+	copied := false
+	for _, key := range keys {
+		source, name := sourceKey(key)
+		if source == "" {
+			continue
+		}
+		if !copied {
+			form.Value = cloneValues(form.Value)
+			files := make(map[string][]*multipart.FileHeader, len(form.File))
+			for k, f := range form.File {
+				files[k] = f
+			}
+			form.File = files
+			copied = true
+		}
+		switch source {
+		case sourceQuery:
+			if r.URL != nil {
+				addMatchingValues(form, source, name, r.URL.Query())
+			}
+		case sourceBody:
+			addMatchingValues(form, source, name, body)
+			if r.MultipartForm != nil {
+				for k, f := range r.MultipartForm.File {
+					if matchAnyKey([]string{name}, k) {
+						form.File[source+":"+k] = f
+					}
+				}
+			}
+		case sourceHeader:
+			if v := r.Header.Values(name); len(v) > 0 {
+				form.Value[key] = append([]string(nil), v...)
+			}
+		case sourceCookie:
+			for _, c := range r.Cookies() {
+				if c.Name == name {
+					form.Value[key] = append(form.Value[key], c.Value)
+				}
+			}
+		case sourcePath:
+			if pv, ok := interface{}(r).(interface{ PathValue(string) string }); ok {
+				if v := pv.PathValue(name); v != "" {
+					form.Value[key] = []string{v}
+				}
+			}
+		}
+	}
+}
+
+// addMatchingValues sets the values matching pattern (see formKeys) in form, under keys qualified by source.
+func addMatchingValues(form *multipart.Form, source, pattern string, values map[string][]string) {
+	for k, v := range values {
+		if matchAnyKey([]string{pattern}, k) {
+			form.Value[source+":"+k] = append([]string(nil), v...)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package check
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gowww/i18n"
+)
+
+func TestCheckRequestSources(t *testing.T) {
+	c := Checker{
+		"query:page":          {Required, Integer},
+		"body:page":           {Required},
+		"body:tags[*]":        {Alpha},
+		"header:X-Request-ID": {Required, MinLen(8)},
+		"cookie:session":      {Required},
+		"path:id":             {Required, Integer},
+		"page":                {MaxCount(1)},
+	}
+	r := httptest.NewRequest("POST", "/users/abc?page=x", strings.NewReader("tags[0]=a&tags[1]=b2&page=2"))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	r.Header.Set("X-Request-Id", "1234")
+	r.AddCookie(&http.Cookie{Name: "session", Value: "token"})
+	pathValue := false
+	if pv, ok := interface{}(r).(interface{ SetPathValue(string, string) }); ok {
+		pv.SetPathValue("id", "abc")
+		pathValue = true
+	}
+
+	got := c.CheckRequest(r)
+	want := Errors{
+		"query:page":          {{Error: ErrNotInteger, Indexes: []int{0}}},
+		"body:tags[1]":        {{Error: ErrNotAlpha, Indexes: []int{0}}},
+		"header:X-Request-ID": {{Error: ErrMinLen, Args: []interface{}{i18n.TransInt(8)}, Indexes: []int{0}}},
+		"path:id":             {{Error: ErrNotInteger, Indexes: []int{0}}},
+		"page":                {{Error: ErrMaxCount, Args: []interface{}{i18n.TransInt(1)}}},
+	}
+	if !pathValue {
+		want["path:id"] = []*Error{{Error: ErrRequired}}
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("Checker.CheckRequest:\nwant %v\ngot  %v", want, got)
+	}
+	if _, ok := r.Form["query:page"]; ok {
+		t.Errorf("Checker.CheckRequest: request form changed")
+	}
+}
+
+func TestCheckRequestSourcesStrict(t *testing.T) {
+	c := Checker{
+		"query:page":   {Required, Integer},
+		"body:tags[*]": {Alpha},
+	}
+	r := httptest.NewRequest("POST", "/?page=2", strings.NewReader("tags[0]=a&admin=1"))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	want := Errors{"admin": {{Error: ErrUnknownField}}}
+	if got := c.CheckRequest(r, Strict()); !reflect.DeepEqual(want, got) {
+		t.Errorf("Checker.CheckRequest:\nwant %v\ngot  %v", want, got)
+	}
+
+	c = Checker{"query:role": {Required, In("user")}}
+	r = httptest.NewRequest("POST", "/?role=user", strings.NewReader("role=admin"))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	want = Errors{"role": {{Error: ErrUnknownField}}}
+	if got := c.CheckRequest(r, Strict()); !reflect.DeepEqual(want, got) {
+		t.Errorf("Checker.CheckRequest with body value:\nwant %v\ngot  %v", want, got)
+	}
+}
@@ -0,0 +1,223 @@
+package check
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"net/url"
+)
+
+// streamErrors are the errors that can only remain while a file is received, so they abort a streamed upload.
+var streamErrors = []*ErrorID{ErrBadFileType, ErrMaxCount, ErrMaxFileSize, ErrMaxTotalFileSize, ErrNotImage}
+
+var errStreamAborted = errors.New("check: upload aborted")
+
+// streamChunkSize is the size of the chunks read from a file part, after which the rules are applied again.
+const streamChunkSize = 32 << 10 // 32 KB
+
+// streamRequest reads the multipart body of r part by part and applies the rules of c for each file part as it arrives.
+// On the first error of streamErrors, reading stops and the errors are returned.
+// Otherwise, the form is parsed as by http.Request.ParseMultipartForm and set in r.
+func streamRequest(r *http.Request, c OrderedChecker, maxMemory int64) (Errors, error) {
+	if mt, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mt != "multipart/form-data" {
+		return nil, http.ErrNotMultipart
+	}
+	mr, err := r.MultipartReader()
+	if err != nil {
+		r.MultipartForm = nil // Set by MultipartReader even on failure, it would make r.ParseMultipartForm and r.FormFile fail.
+		return nil, err
+	}
+	pr, pw := io.Pipe()
+	mw := multipart.NewWriter(pw)
+	type result struct {
+		form *multipart.Form
+		err  error
+	}
+	done := make(chan result, 1)
+	go func() {
+		form, err := multipart.NewReader(pr, mw.Boundary()).ReadForm(maxMemory)
+		pr.CloseWithError(err) // Unblocks the writer if reading stops early.
+		done <- result{form, err}
+	}()
+	s := &stream{checker: c, writer: mw, values: make(map[string][]string), files: make(map[string][]*multipart.FileHeader)}
+	errs, err := s.run(mr)
+	switch {
+	case err != nil:
+		pw.CloseWithError(err)
+	case errs != nil:
+		pw.CloseWithError(errStreamAborted)
+	default:
+		if err = mw.Close(); err == nil {
+			pw.Close()
+		} else {
+			pw.CloseWithError(err)
+		}
+	}
+	res := <-done
+	if errs != nil || err != nil {
+		if res.form != nil {
+			res.form.RemoveAll()
+		}
+		return errs, err
+	}
+	if res.err != nil {
+		return nil, res.err
+	}
+	r.MultipartForm = res.form
+	if r.PostForm == nil {
+		r.PostForm = make(url.Values)
+	}
+	for k, v := range res.form.Value {
+		r.PostForm[k] = append(r.PostForm[k], v...)
+	}
+	return nil, nil
+}
+
+// A stream copies the parts of a multipart body while checking its files.
+type stream struct {
+	checker OrderedChecker
+	writer  *multipart.Writer
+	values  map[string][]string
+	files   map[string][]*multipart.FileHeader // Probes of the files received so far (see probeFile).
+}
+
+// run copies all the parts of mr.
+func (s *stream) run(mr *multipart.Reader) (Errors, error) {
+	for {
+		p, err := mr.NextPart()
+		if err == io.EOF {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		w, err := s.writer.CreatePart(p.Header)
+		if err != nil {
+			return nil, err
+		}
+		name := p.FormName()
+		if p.FileName() == "" {
+			b, err := ioutil.ReadAll(io.TeeReader(p, w))
+			if err != nil {
+				return nil, err
+			}
+			s.values[name] = append(s.values[name], string(b))
+			continue
+		}
+		if errs, err := s.copyFile(w, p, name); errs != nil || err != nil {
+			return errs, err
+		}
+	}
+}
+
+// copyFile copies file part p to w and applies the rules of its key after its first 512 bytes and after each chunk.
+func (s *stream) copyFile(w io.Writer, p *multipart.Part, name string) (Errors, error) {
+	rules := s.rules(name)
+	head := make([]byte, 0, 512)
+	buf := make([]byte, streamChunkSize)
+	var probe *multipart.FileHeader
+	var size int64
+	for {
+		n, rerr := p.Read(buf)
+		if n > 0 {
+			if _, err := w.Write(buf[:n]); err != nil {
+				return nil, err
+			}
+			size += int64(n)
+			if len(head) < cap(head) {
+				head = append(head, buf[:minInt(n, cap(head)-len(head))]...)
+			}
+		}
+		if rerr != nil && rerr != io.EOF {
+			return nil, rerr
+		}
+		if len(rules) > 0 && (len(head) == cap(head) || rerr == io.EOF) {
+			if probe == nil {
+				var err error
+				if probe, err = probeFile(p.Header, head); err != nil {
+					return nil, err
+				}
+			}
+			probe.Size = size
+			if errs := s.apply(rules, name, probe); errs != nil {
+				return errs, nil
+			}
+		}
+		if rerr == io.EOF {
+			if probe != nil {
+				s.files[name] = append(s.files[name], probe)
+			}
+			return nil, nil
+		}
+	}
+}
+
+// rules returns the rules of the checker keys matching name.
+func (s *stream) rules(name string) [][]Rule {
+	var rules [][]Rule
+	for _, kr := range s.checker {
+		if kr.Key == name || matchAnyKey([]string{kr.Key}, name) {
+			rules = append(rules, kr.Rules)
+		}
+	}
+	return rules
+}
+
+// apply applies rules to the values and files received so far for name, with the file being received, and returns the errors of streamErrors.
+func (s *stream) apply(rules [][]Rule, name string, probe *multipart.FileHeader) Errors {
+	form := &multipart.Form{
+		Value: map[string][]string{name: s.values[name]},
+		File:  map[string][]*multipart.FileHeader{name: append(s.files[name][:len(s.files[name]):len(s.files[name])], probe)},
+	}
+	var errs Errors
+	for _, rr := range rules {
+		scratch := make(Errors)
+		applyRules(context.Background(), scratch, form, name, rr)
+		for _, err := range scratch[name] {
+			for _, id := range streamErrors {
+				if err.Error == id {
+					if errs == nil {
+						errs = make(Errors)
+					}
+					errs.Add(name, err)
+				}
+			}
+		}
+	}
+	return errs
+}
+
+// probeFile returns a file header made from the header and the first bytes of a part, so the file rules can open it and sniff its type.
+func probeFile(header textproto.MIMEHeader, head []byte) (*multipart.FileHeader, error) {
+	b := new(bytes.Buffer)
+	w := multipart.NewWriter(b)
+	pw, err := w.CreatePart(header)
+	if err != nil {
+		return nil, err
+	}
+	pw.Write(head)
+	w.Close()
+	form, err := multipart.NewReader(b, w.Boundary()).ReadForm(int64(len(head)) + 1<<20)
+	if err != nil {
+		return nil, err
+	}
+	for _, files := range form.File {
+		if len(files) > 0 {
+			return files[0], nil
+		}
+	}
+	return nil, errors.New("check: cannot probe file part")
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
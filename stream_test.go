@@ -0,0 +1,108 @@
+package check
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// countReader counts the bytes read from r.
+type countReader struct {
+	r io.Reader
+	n int64
+}
+
+func (cr *countReader) Read(p []byte) (int, error) {
+	n, err := cr.r.Read(p)
+	cr.n += int64(n)
+	return n, err
+}
+
+// testStreamRequest returns a request with a multipart body made of a "name" value and a "file" file of size bytes of content.
+func testStreamRequest(t *testing.T, content string, size int) (*http.Request, *countReader) {
+	pr, pw := io.Pipe()
+	w := multipart.NewWriter(pw)
+	go func() {
+		w.WriteField("name", "foo")
+		fw, _ := w.CreateFormFile("file", "file.txt")
+		for written := 0; written < size; written += len(content) {
+			if _, err := io.WriteString(fw, content); err != nil {
+				return
+			}
+		}
+		w.Close()
+		pw.Close()
+	}()
+	cr := &countReader{r: pr}
+	r, err := http.NewRequest("POST", "/", cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.Header.Set("Content-Type", w.FormDataContentType())
+	return r, cr
+}
+
+func TestStream(t *testing.T) {
+	content := strings.Repeat("text ", 200)
+	c := Checker{
+		"name": {Required},
+		"file": {Required, FileType("text/plain"), MaxFileSize(100 << 10)},
+	}
+
+	r, cr := testStreamRequest(t, content, 50<<10)
+	form, errs := c.CheckRequestClean(r, Stream())
+	if errs.NotEmpty() {
+		t.Fatalf("Stream: want no errors, got %v", errs)
+	}
+	f, err := form.File["file"][0].Open()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := ioutil.ReadAll(f)
+	f.Close()
+	if len(b) < 50<<10 || !bytes.HasPrefix(b, []byte(content)) || form.Value["name"][0] != "foo" {
+		t.Errorf("Stream: form not fully parsed (%d bytes file)", len(b))
+	}
+	form.RemoveAll()
+
+	r, cr = testStreamRequest(t, content, 100<<20)
+	errs, err = c.CheckRequestContext(r, Stream())
+	if err != nil || errs.First("file") == nil || errs.First("file").Error != ErrMaxFileSize {
+		t.Errorf("Stream: want %s error, got %v and %v", ErrMaxFileSize.ID, errs, err)
+	}
+	if cr.n > 1<<20 {
+		t.Errorf("Stream: want upload aborted early, got %d bytes read", cr.n)
+	}
+
+	r, cr = testStreamRequest(t, "\x89PNG\r\n\x1a\n", 100<<20)
+	errs, _ = c.CheckRequestContext(r, Stream())
+	if e := errs.First("file"); e == nil || e.Error != ErrBadFileType {
+		t.Errorf("Stream: want %s error, got %v", ErrBadFileType.ID, errs)
+	}
+	if cr.n > 1<<20 {
+		t.Errorf("Stream: want upload aborted early, got %d bytes read", cr.n)
+	}
+}
+
+func TestStreamNotMultipart(t *testing.T) {
+	c := Checker{"name": {Required}}
+
+	r, _ := http.NewRequest("POST", "/", strings.NewReader("name=foo"))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	errs, err := c.CheckRequestContext(r, Stream())
+	if err != nil || errs.NotEmpty() {
+		t.Errorf("Stream: want no error for an urlencoded body, got %v and %v", errs, err)
+	}
+	if _, _, err = r.FormFile("file"); err != http.ErrNotMultipart {
+		t.Errorf("Stream: want request usable by FormFile, got %v", err)
+	}
+
+	r, _ = http.NewRequest("GET", "/?name=foo", nil)
+	if errs, err = c.CheckRequestContext(r, Stream()); err != nil || errs.NotEmpty() {
+		t.Errorf("Stream: want no error for a GET request, got %v and %v", errs, err)
+	}
+}
@@ -0,0 +1,276 @@
+package check
+
+import (
+	"encoding"
+	"fmt"
+	"mime/multipart"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	typeFileHeader    = reflect.TypeOf((*multipart.FileHeader)(nil))
+	typeFileHeaders   = reflect.TypeOf([]*multipart.FileHeader(nil))
+	typeTime          = reflect.TypeOf(time.Time{})
+	typeTextMarshaler = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// FromStruct returns a Checker made from the "check" tags of struct v fields.
+//
+// Tag is a rules expression (see ParseRules).
+//
+//	type User struct {
+//		Email    string `json:"email" check:"required|email|maxlen:255"`
+//		Stars    int    `json:"stars" check:"range:1,5"`
+//		Password string `form:"password" check:"required|rangelen:8,64"`
+//		Confirm  string `form:"confirm" check:"same:password"`
+//	}
+//
+// Keys are the field names from the "form" tag, the "json" tag or the field name itself, in this order.
+// Nested structs fields are prefixed by their parent key and a dot (like "address.city"), except for embedded structs.
+// Fields of the structs in a slice or an array are prefixed by their parent key and index (like "items.0.sku"), and their rules are for all indexes (like "items.*.sku").
+// The Unique rule cannot be used in a tag as it needs a database.
+// A field of a struct type that contains it (like the next node of a linked list) has no rules.
+//
+// It panics if v is not a struct or if a tag is malformed.
+func FromStruct(v interface{}) Checker {
+	t := reflect.TypeOf(v)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("check: %T is not a struct", v))
+	}
+	c := make(Checker)
+	structRules(c, "", t, make(map[reflect.Type]bool))
+	return c
+}
+
+// CheckStruct makes the check for struct v fields and returns errors.
+// Fields are converted to values (or files) under the same keys as in FromStruct, so
+//
+//	errs := check.FromStruct(user).CheckStruct(user)
+//
+// checks a struct against its own tags.
+// Nil pointers have no value, zero times are empty, numbers and booleans are formatted by package strconv and slices have multiple values.
+// As in FromStruct, a field of a struct type that contains it has no values, so a cyclic value can be checked.
+//
+// Result is guaranteed to be non-nil.
+func (c Checker) CheckStruct(v interface{}, opts ...Option) Errors {
+	return c.Check(structForm(v), opts...)
+}
+
+// structForm returns a form made from struct v fields.
+func structForm(v interface{}) *multipart.Form {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("check: %T is not a struct", v))
+	}
+	form := &multipart.Form{Value: make(map[string][]string), File: make(map[string][]*multipart.FileHeader)}
+	structValues(form, "", rv, make(map[reflect.Type]bool))
+	return form
+}
+
+// structKey returns the checking key of a struct field and tells if the field must be used.
+func structKey(f reflect.StructField) (string, bool) {
+	if f.PkgPath != "" && !f.Anonymous { // Unexported field.
+		return "", false
+	}
+	for _, tag := range []string{"form", "json"} {
+		name := f.Tag.Get(tag)
+		if i := strings.IndexByte(name, ','); i != -1 {
+			name = name[:i]
+		}
+		if name == "-" {
+			return "", false
+		}
+		if name != "" {
+			return name, true
+		}
+	}
+	return f.Name, true
+}
+
+// structNested tells if a struct field of type t must be walked through instead of being used as a value.
+func structNested(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && t != typeTime && !t.Implements(typeTextMarshaler) && !reflect.PtrTo(t).Implements(typeTextMarshaler)
+}
+
+// structElem returns the element type of a slice or array type t (pointers dereferenced) and tells if its elements must be walked through (see structNested).
+func structElem(t reflect.Type) (reflect.Type, bool) {
+	if t.Kind() != reflect.Slice && t.Kind() != reflect.Array {
+		return nil, false
+	}
+	et := t.Elem()
+	for et.Kind() == reflect.Ptr {
+		et = et.Elem()
+	}
+	return et, structNested(et) && et != typeFileHeader.Elem()
+}
+
+// structRules adds the rules of struct t fields to c.
+// A struct type already walked through by the parents of t (like a linked list node) is skipped, so a recursive type has the rules of its first level only.
+func structRules(c Checker, prefix string, t reflect.Type, parents map[reflect.Type]bool) {
+	parents[t] = true
+	defer delete(parents, t)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		key, ok := structKey(f)
+		if !ok {
+			continue
+		}
+		ft := f.Type
+		for ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if et, ok := structElem(ft); ok && !parents[et] && f.PkgPath == "" {
+			structRules(c, prefix+key+".*.", et, parents)
+		}
+		if structNested(ft) {
+			if parents[ft] {
+				continue
+			}
+			if f.Anonymous && f.Tag.Get("form") == "" && f.Tag.Get("json") == "" {
+				structRules(c, prefix, ft, parents)
+			} else {
+				structRules(c, prefix+key+".", ft, parents)
+			}
+			continue
+		}
+		if f.PkgPath != "" { // Unexported embedded non-struct field.
+			continue
+		}
+		tag := f.Tag.Get("check")
+		if tag == "" {
+			continue
+		}
+		rules, err := ParseRules(tag)
+		if err != nil {
+			panic(fmt.Sprintf("%v (in tag of field %s)", err, f.Name))
+		}
+		c[prefix+key] = append(c[prefix+key], rules...)
+	}
+}
+
+// structValues adds the values and files of struct v fields to form.
+// As in structRules, a struct type already walked through by the parents of v is skipped, so a cyclic value (like a circular linked list) ends.
+func structValues(form *multipart.Form, prefix string, v reflect.Value, parents map[reflect.Type]bool) {
+	t := v.Type()
+	parents[t] = true
+	defer delete(parents, t)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		key, ok := structKey(f)
+		if !ok {
+			continue
+		}
+		key = prefix + key
+		fv := v.Field(i)
+		if !fv.CanInterface() && !f.Anonymous {
+			continue
+		}
+		switch fv.Type() {
+		case typeFileHeader:
+			if !fv.IsNil() {
+				form.File[key] = append(form.File[key], fv.Interface().(*multipart.FileHeader))
+			}
+			continue
+		case typeFileHeaders:
+			form.File[key] = append(form.File[key], fv.Interface().([]*multipart.FileHeader)...)
+			continue
+		}
+		if fv, ok = indirect(fv); !ok {
+			continue
+		}
+		if structNested(fv.Type()) {
+			if parents[fv.Type()] {
+				continue
+			}
+			if f.Anonymous && f.Tag.Get("form") == "" && f.Tag.Get("json") == "" {
+				structValues(form, prefix, fv, parents)
+			} else {
+				structValues(form, key+".", fv, parents)
+			}
+			continue
+		}
+		if f.PkgPath != "" {
+			continue
+		}
+		if et, ok := structElem(fv.Type()); ok {
+			if parents[et] {
+				continue
+			}
+			for j := 0; j < fv.Len(); j++ {
+				if ev, ok := indirect(fv.Index(j)); ok {
+					structValues(form, key+"."+strconv.Itoa(j)+".", ev, parents)
+				}
+			}
+			continue
+		}
+		if fv.Kind() == reflect.Slice && fv.Type().Elem().Kind() != reflect.Uint8 || fv.Kind() == reflect.Array {
+			for j := 0; j < fv.Len(); j++ {
+				if s, ok := structValue(fv.Index(j)); ok {
+					form.Value[key] = append(form.Value[key], s)
+				}
+			}
+			continue
+		}
+		if s, ok := structValue(fv); ok {
+			form.Value[key] = append(form.Value[key], s)
+		}
+	}
+}
+
+// structValue returns the string representation of a struct field value and tells if it has one.
+func structValue(v reflect.Value) (string, bool) {
+	v, ok := indirect(v)
+	if !ok {
+		return "", false
+	}
+	if v.Type() == typeTime {
+		t := v.Interface().(time.Time)
+		if t.IsZero() {
+			return "", true
+		}
+		return t.Format(time.RFC3339), true
+	}
+	if v.Type().Implements(typeTextMarshaler) {
+		b, err := v.Interface().(encoding.TextMarshaler).MarshalText()
+		return string(b), err == nil
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), true
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(v.Uint(), 10), true
+	case reflect.Float32:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 32), true
+	case reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 64), true
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return string(v.Bytes()), true
+		}
+	}
+	return "", false
+}
+
+// indirect dereferences pointers and interfaces of v and tells if the final value exists.
+func indirect(v reflect.Value) (reflect.Value, bool) {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return v, false
+		}
+		v = v.Elem()
+	}
+	return v, true
+}
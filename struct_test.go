@@ -0,0 +1,100 @@
+package check
+
+import (
+	"reflect"
+	"testing"
+)
+
+type testStructAddress struct {
+	City string `json:"city" check:"required|alpha"`
+}
+
+type testStructItem struct {
+	SKU      string `json:"sku" check:"required"`
+	Quantity int    `json:"quantity" check:"min:1"`
+}
+
+type testStruct struct {
+	Email    string            `json:"email,omitempty" check:"required|email|maxlen:255"`
+	Stars    int               `form:"stars" json:"rating" check:"range:3,5"`
+	Password string            `form:"password" check:"rangelen:3,64"`
+	Confirm  string            `form:"confirm" check:"same:password"`
+	Tags     []string          `json:"tags" check:"maxlen:3"`
+	Phone    *string           `json:"phone" check:"phone"`
+	Meeting  string            `json:"meeting" check:"datetime:02/01/2006 15:04"`
+	Address  testStructAddress `json:"address"`
+	Items    []*testStructItem `json:"items" check:"maxcount:2"`
+	Ignored  string            `json:"-" check:"required"`
+	private  string
+}
+
+func TestCheckerCheckStruct(t *testing.T) {
+	v := &testStruct{
+		Stars:    2,
+		Password: "secret",
+		Confirm:  "secrets",
+		Tags:     []string{"foo", "quux"},
+		Meeting:  "2020-01-31 10:00",
+		Items:    []*testStructItem{{SKU: "A1", Quantity: 1}, {Quantity: 0}},
+		private:  "private",
+	}
+	want := Errors{
+		"email":            {{Error: ErrRequired}},
+		"stars":            {{Error: ErrMin, Args: []interface{}{"3"}}},
+		"confirm":          {{Error: ErrNotSame, Args: []interface{}{"password"}}},
+		"tags":             {{Error: ErrMaxLen, Args: []interface{}{"3"}}},
+		"meeting":          {{Error: ErrNotDateTime}},
+		"address.city":     {{Error: ErrRequired}},
+		"items.1.sku":      {{Error: ErrRequired}},
+		"items.1.quantity": {{Error: ErrMin, Args: []interface{}{"1"}}},
+	}
+	got := FromStruct(v).CheckStruct(v, Strict()).StringMap()
+	if !reflect.DeepEqual(want.StringMap(), got) {
+		t.Errorf("Checker.CheckStruct:\nwant %v\ngot  %v", want.StringMap(), got)
+	}
+}
+
+type testStructNode struct {
+	Name string          `json:"name" check:"required"`
+	Next *testStructNode `json:"next"`
+}
+
+func TestFromStructRecursive(t *testing.T) {
+	c := FromStruct(testStructNode{})
+	if len(c) != 1 || len(c["name"]) != 1 {
+		t.Errorf("FromStruct: want rules for %q only, got %v", "name", c.Keys())
+	}
+	want := Errors{"name": {{Error: ErrRequired}}}
+	if got := c.CheckStruct(&testStructNode{Next: &testStructNode{Name: "foo"}}, Strict()); !reflect.DeepEqual(want, got) {
+		t.Errorf("Checker.CheckStruct:\nwant %v\ngot  %v", want, got)
+	}
+	cyclic := &testStructNode{Name: "foo"}
+	cyclic.Next = cyclic
+	if got := c.CheckStruct(cyclic, Strict()); !reflect.DeepEqual(Errors{}, got) {
+		t.Errorf("Checker.CheckStruct (cyclic): want no errors, got %v", got)
+	}
+}
+
+func TestFromStructPanics(t *testing.T) {
+	for _, v := range []interface{}{
+		"string",
+		struct {
+			A string `check:"unknown"`
+		}{},
+		struct {
+			A string `check:"range:1"`
+		}{},
+		struct {
+			A string `check:"maxlen:a"`
+		}{},
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("FromStruct(%#v): want panic", v)
+				}
+			}()
+			FromStruct(v)
+		}()
+	}
+}